@@ -1 +1,11 @@
 # Include custom targets and environment variables here
+
+## Checks the area and mob files for errors.
+.PHONY: lint-assets
+lint-assets:
+	$(GO) run ./cmd/mudlint -assets $(ASSETS_DIR)
+
+## Generates the JSON Schema of the asset files.
+.PHONY: schema
+schema:
+	$(GO) run ./cmd/mudschema -src server/mud -out schema
@@ -0,0 +1,40 @@
+// mudlint checks the mattermud asset files and reports all the problems found at once.
+//
+// Usage:
+//
+//	mudlint [-assets dir] [-start room_id] [-strict]
+//
+// It exits with a non-zero status if any error is found, or if any warning is found when running with -strict.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mattermost/mattermost-plugin-mattermud/server/mud"
+)
+
+func main() {
+	assetsPath := flag.String("assets", "assets", "directory containing the areas and mobs folders")
+	startRoom := flag.String("start", "", "room from where all rooms should be reachable (default: the recall room of the start area)")
+	strict := flag.Bool("strict", false, "fail on warnings too")
+	flag.Parse()
+
+	problems := mud.LintAssets(*assetsPath, *startRoom)
+
+	errors, warnings := 0, 0
+	for _, p := range problems {
+		fmt.Println(p.String())
+		if p.Severity == mud.LintError {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+	fmt.Printf("%d errors, %d warnings\n", errors, warnings)
+
+	if errors > 0 || (*strict && warnings > 0) {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,323 @@
+// mudschema generates the JSON Schema of the mattermud asset files from the structs used to load them.
+//
+// Usage:
+//
+//	mudschema [-src dir] [-out dir]
+//
+// Field descriptions are taken from the doc comments of the struct fields found on the source directory.
+package main
+
+import (
+	"encoding"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mattermud/server/mud"
+)
+
+const schemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// assetSchema describes each file generated
+type assetSchema struct {
+	file        string
+	title       string
+	description string
+	root        reflect.Type
+}
+
+var assetSchemas = []assetSchema{
+	{
+		file:        "area.schema.json",
+		title:       "Mattermud area",
+		description: "An area file under assets/areas.",
+		root:        reflect.TypeOf(mud.JSONArea{}),
+	},
+	{
+		file:        "items.schema.json",
+		title:       "Mattermud items",
+		description: "An item file under assets/items, containing a list of items.",
+		root:        reflect.TypeOf([]*mud.Item{}),
+	},
+	{
+		file:        "mobs.schema.json",
+		title:       "Mattermud mobs",
+		description: "A mob file under assets/mobs, containing a list of mobs.",
+		root:        reflect.TypeOf([]*mud.Mob{}),
+	},
+	{
+		file:        "quests.schema.json",
+		title:       "Mattermud quests",
+		description: "A quest file under assets/quests, containing a list of quests.",
+		root:        reflect.TypeOf([]*mud.Quest{}),
+	},
+	{
+		file:        "races.schema.json",
+		title:       "Mattermud races",
+		description: "The assets/races.json file, containing the list of races.",
+		root:        reflect.TypeOf([]*mud.Race{}),
+	},
+	{
+		file:        "classes.schema.json",
+		title:       "Mattermud classes",
+		description: "The assets/classes.json file, containing the list of classes.",
+		root:        reflect.TypeOf([]*mud.Class{}),
+	},
+	{
+		file:        "affixes.schema.json",
+		title:       "Mattermud affixes",
+		description: "The assets/affixes.json file, containing the list of affixes items can roll.",
+		root:        reflect.TypeOf([]*mud.Affix{}),
+	},
+}
+
+// requiredFields lists the JSON fields that must be present on each struct
+var requiredFields = map[string][]string{
+	"JSONArea":         {"area_id", "recall_room", "rooms"},
+	"JSONRoom":         {"id", "name", "short_description"},
+	"JSONNeighbour":    {"id"},
+	"Mob":              {"id", "max_hp"},
+	"Item":             {"id", "name"},
+	"Drop":             {"item_id", "probability"},
+	"Equipment":        {"slot"},
+	"Consumable":       {"kind"},
+	"JSONReset":        {"type", "room"},
+	"ExtraDescription": {"keywords", "description"},
+	"NPC":              {"id", "room"},
+	"Topic":            {"keywords", "responses"},
+	"Condition":        {"type"},
+	"DialogueAction":   {"type"},
+	"ShopStock":        {"item_id"},
+	"Quest":            {"id", "name", "objectives"},
+	"Objective":        {"type"},
+	"Race":             {"id", "name"},
+	"Class":            {"id", "name"},
+	"Affix":            {"id", "name"},
+}
+
+// representations maps the types that have their own JSON marshalling to the type they are marshalled as
+var representations = map[reflect.Type]reflect.Type{
+	reflect.TypeOf(mud.Stats{}): reflect.TypeOf(mud.StatsJSON{}),
+	reflect.TypeOf(time.Time{}): reflect.TypeOf(""),
+}
+
+// fieldOverrides modifies the schema generated for certain struct fields
+var fieldOverrides = map[string]func(schema map[string]interface{}){
+	"Effect.ExpiresAt": func(schema map[string]interface{}) {
+		schema["format"] = "date-time"
+	},
+	"JSONRoom.Neighbours": func(schema map[string]interface{}) {
+		schema["propertyNames"] = map[string]interface{}{
+			"enum": []string{"north", "south", "east", "west", "up", "down"},
+		}
+	},
+}
+
+type generator struct {
+	comments    map[string]string
+	definitions map[string]interface{}
+}
+
+func main() {
+	src := flag.String("src", filepath.Join("server", "mud"), "directory with the source of the mud package")
+	out := flag.String("out", "schema", "directory where the schemas are written")
+	flag.Parse()
+
+	comments, err := fieldComments(*src)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cannot parse sources:", err)
+		os.Exit(1)
+	}
+
+	if err = os.MkdirAll(*out, 0755); err != nil {
+		fmt.Fprintln(os.Stderr, "cannot create output directory:", err)
+		os.Exit(1)
+	}
+
+	for _, a := range assetSchemas {
+		g := &generator{
+			comments:    comments,
+			definitions: make(map[string]interface{}),
+		}
+		schema := g.schemaFor(a.root)
+		schema["$schema"] = schemaDraft
+		schema["title"] = a.title
+		schema["description"] = a.description
+		schema["definitions"] = g.definitions
+
+		b, err := json.MarshalIndent(schema, "", "    ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "cannot marshal schema:", err)
+			os.Exit(1)
+		}
+		if err = ioutil.WriteFile(filepath.Join(*out, a.file), append(b, '\n'), 0644); err != nil {
+			fmt.Fprintln(os.Stderr, "cannot write schema:", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// fieldComments returns the doc comment of each struct field on the package, keyed by Type.Field
+func fieldComments(dir string) (map[string]string, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make(map[string]string)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				spec, ok := n.(*ast.TypeSpec)
+				if !ok {
+					return true
+				}
+				st, ok := spec.Type.(*ast.StructType)
+				if !ok {
+					return true
+				}
+				for _, field := range st.Fields.List {
+					if field.Doc == nil {
+						continue
+					}
+					for _, name := range field.Names {
+						comments[spec.Name.Name+"."+name.Name] = strings.TrimSpace(field.Doc.Text())
+					}
+				}
+				return true
+			})
+		}
+	}
+	return comments, nil
+}
+
+func (g *generator) schemaFor(t reflect.Type) map[string]interface{} {
+	if representation, ok := representations[t]; ok {
+		t = representation
+	}
+
+	if names := textEnum(t); names != nil {
+		return map[string]interface{}{
+			"type": "string",
+			"enum": names,
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schemaFor(t.Elem())
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": g.schemaFor(t.Elem()),
+		}
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": g.schemaFor(t.Elem()),
+		}
+	case reflect.Struct:
+		return g.structRef(t)
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// textEnum returns the names of all the values of integer types marshalled as text, like the equipment slots
+func textEnum(t reflect.Type) []string {
+	if t.Kind() != reflect.Int || !t.Implements(reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()) {
+		return nil
+	}
+
+	names := []string{}
+	for i := 0; ; i++ {
+		v := reflect.New(t).Elem()
+		v.SetInt(int64(i))
+		name, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return names
+		}
+		names = append(names, string(name))
+	}
+}
+
+// structRef adds the definition of the struct if needed, and returns a reference to it
+func (g *generator) structRef(t reflect.Type) map[string]interface{} {
+	ref := map[string]interface{}{"$ref": "#/definitions/" + t.Name()}
+	if _, ok := g.definitions[t.Name()]; ok {
+		return ref
+	}
+	// Reserve the name first so recursive types do not loop forever
+	g.definitions[t.Name()] = nil
+
+	properties := make(map[string]interface{})
+	g.addFields(t, properties)
+
+	definition := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if required, ok := requiredFields[t.Name()]; ok {
+		sort.Strings(required)
+		definition["required"] = required
+	}
+	g.definitions[t.Name()] = definition
+	return ref
+}
+
+// addFields adds the properties of all the fields of t, flattening embedded structs
+func (g *generator) addFields(t reflect.Type, properties map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			g.addFields(field.Type, properties)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := g.schemaFor(field.Type)
+		if _, isRef := schema["$ref"]; isRef {
+			// Siblings of $ref are ignored, so the description goes on a wrapper
+			schema = map[string]interface{}{"allOf": []interface{}{schema}}
+		}
+		key := t.Name() + "." + field.Name
+		if comment, ok := g.comments[key]; ok {
+			schema["description"] = comment
+		}
+		if override, ok := fieldOverrides[key]; ok {
+			override(schema)
+		}
+		properties[name] = schema
+	}
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/mattermost/mattermost-plugin-mattermud/server/mud"
@@ -14,9 +13,44 @@ func getIngameHelp() string {
 	return `Ingame commands:
 	n, s, e, w, north, south, east, west: Movement commands
 	look: Show again the description of the room, with extra information
-	status: Shows your current HP
-	kill [mob]: Starts attacking the mob with that name. Example: kill bunny
-	sleep: Starts to sleep. This will silence almost all notifications from the game
+	look [target]: Looks at a mob, player, item or detail of the room, or at the room in a direction. Example: look statue
+	look in [container]: Shows what is inside a bag, chest or corpse. Example: look in corpse
+	examine [item]: Shows the description and properties of an item. Example: examine sword
+	status: Shows your current HP, mana, movement points and gold
+	score: Shows your character sheet: race, class, level, experience, stats, attack, defense, effects and equipment
+	prompt [on|off]: Shows or hides your HP, mana and movement points after every message. Without on or off, it switches between them
+	inventory, i: Shows the items you are carrying and how much they weigh. Carrying too much slows you down and hinders you in combat
+	get [item]: Picks up an item from the ground. Example: get sword
+	get [item] from [container]: Takes an item, or all of them, out of a container. Example: get all from corpse
+	put [item] in [container]: Puts an item inside a container. Example: put bread in bag
+	drop [item]: Drops an item on the ground. Example: drop sword
+	wear [item], wield [item]: Equips an item from your inventory. Some races and classes cannot use every slot. Example: wield sword
+	remove [item]: Takes off an equipped item. Example: remove helmet
+	quaff [potion], read [scroll], eat [food], drink [drink]: Consumes an item to heal, gain an effect for a while or recall to safety. In a fight, it takes your next turn. Example: quaff potion
+	use [wand|staff]: Uses a wand or a staff, spending one of its charges. Example: use wand
+	open [direction|container], close [direction|container]: Opens or closes a door or a container. Example: open east
+	unlock [direction|container], lock [direction|container]: Unlocks or locks a door or a container, if you have the key. Example: unlock chest
+	areas: Lists all the areas of the world
+	talk [npc]: Starts a conversation with someone. Example: talk priest
+	ask [npc] about [subject]: Asks someone about something. Example: ask captain about forest
+	quest, quest list: Shows your active and completed quests
+	quest info [quest]: Shows the objectives and reward of a quest, by number or name. Example: quest info 1
+	quest abandon [quest]: Gives up an active quest, losing its progress. Example: quest abandon 1
+	list: Shows the items for sale in the shop
+	buy [item], sell [item]: Buys or sells an item in the shop. Example: buy sword
+	value [item]: Asks the shop how much it would pay for an item. Example: value carrot
+	follow [player]: Follows a player in the same room wherever they go. Use follow self to stop. Example: follow alice
+	group: Shows the HP and location of the members of your group
+	group [player]: Adds a player who is following you to your group. The members follow you into battle and split the experience. Example: group bob
+	ungroup [player]: Removes a member from your group. Without a player, disbands your group or leaves the group you are in
+	gtell [message]: Tells something to all the members of your group. Example: gtell Let's go north
+	kill [mob|player]: Starts attacking the mob or player with that name or keyword, or switches to it while fighting. Example: kill bunny
+	pvp [on|off]: Shows or sets whether you fight other players. Arenas always allow it, and safe rooms like the temple never do
+	cast [spell] [player]: Casts a spell, spending mana. Only mages know them. Example: cast resurrect alice brings a ghost back to life
+	deaths: Shows how and where you died lately. Ghosts come back to life at a temple, or with the help of a healer or a mage
+	sit, rest: Sits down or lies down to rest, recovering health faster. You have to stand up to move or fight
+	stand: Stands up
+	sleep: Starts to sleep. This will silence almost all notifications from the game, and you recover health the fastest. Beware, aggressive mobs love to catch sleepers
 	wake: You wake up
 	say [something you want to say]: Says something so all players in the same room will see it. Example: say Hello everyone!
 	shout [something you want to shout]: Shouts something so all players in the same area will see it. Example: shout Hello everyone!
@@ -39,6 +73,11 @@ func (p *Plugin) MessageHasBeenPosted(c *plugin.Context, post *model.Post) {
 		return
 	}
 
+	if len(post.FileIds) > 0 && strings.ToLower(strings.TrimSpace(post.Message)) == "reload" {
+		p.handleReloadUpload(post)
+		return
+	}
+
 	player, err := p.world.GetPlayer(post.UserId)
 	if err != nil {
 		p.API.LogError("user not initiated: " + err.Error())
@@ -69,7 +108,15 @@ func (p *Plugin) MessageHasBeenPosted(c *plugin.Context, post *model.Post) {
 	case "west":
 		p.handleMove(player, mud.West)
 	case "look":
-		p.handleLook(player)
+		p.handleLook(player, args[1:])
+	case "examine":
+		p.handleExamine(player, args[1:])
+	case "sit":
+		p.handleSit(player)
+	case "rest":
+		p.handleRest(player)
+	case "stand":
+		p.handleStand(player)
 	case "sleep":
 		p.handleSleep(player)
 	case "wake":
@@ -78,10 +125,66 @@ func (p *Plugin) MessageHasBeenPosted(c *plugin.Context, post *model.Post) {
 		p.handleSay(player, args[1:])
 	case "shout":
 		p.handleShout(player, args[1:])
+	case "talk":
+		p.handleTalk(player, args[1:])
+	case "ask":
+		p.handleAsk(player, args[1:])
+	case "quest", "quests":
+		p.handleQuest(player, args[1:])
+	case "list":
+		p.handleList(player)
+	case "buy":
+		p.handleBuy(player, args[1:])
+	case "sell":
+		p.handleSell(player, args[1:])
+	case "value":
+		p.handleValue(player, args[1:])
 	case "kill":
 		p.handleKill(player, args[1:])
+	case "pvp":
+		p.handlePvP(player, args[1:])
+	case "cast":
+		p.handleCast(player, args[1:])
+	case "deaths":
+		p.handleDeaths(player)
+	case "follow":
+		p.handleFollow(player, args[1:])
+	case "group":
+		p.handleGroup(player, args[1:])
+	case "ungroup":
+		p.handleUngroup(player, args[1:])
+	case "gtell":
+		p.handleGroupTell(player, args[1:])
 	case "status":
 		p.handleStatus(player)
+	case "score":
+		p.handleScore(player)
+	case "prompt":
+		p.handlePrompt(player, args[1:])
+	case "areas":
+		p.handleAreas(player)
+	case "inventory", "i":
+		p.handleInventory(player)
+	case "get", "take":
+		p.handleGet(player, args[1:])
+	case "put":
+		p.handlePut(player, args[1:])
+	case "drop":
+		p.handleDrop(player, args[1:])
+	case "wear", "wield":
+		p.handleWear(player, args[1:])
+	case "remove":
+		p.handleRemove(player, args[1:])
+	case "quaff", "read", "eat", "drink", "use":
+		p.handleConsume(player, strings.ToLower(args[0]), args[1:])
+	case "open":
+		p.handleDoor(player, args[1:], player.OpenDoor, player.OpenContainer)
+	case "close":
+		p.handleDoor(player, args[1:], player.CloseDoor, player.CloseContainer)
+	case "unlock":
+		p.handleDoor(player, args[1:], player.UnlockDoor, player.UnlockContainer)
+	case "lock":
+		p.handleDoor(player, args[1:], player.LockDoor, player.LockContainer)
 	case "help":
 		p.handleHelp(player)
 	default:
@@ -93,8 +196,36 @@ func (p *Plugin) handleMove(player *mud.Player, d mud.Direction) {
 	player.Move(d)
 }
 
-func (p *Plugin) handleLook(player *mud.Player) {
-	player.LookRoom()
+func (p *Plugin) handleLook(player *mud.Player, args []string) {
+	if len(args) == 0 {
+		player.LookRoom()
+		return
+	}
+	if d, ok := parseDirection(args[0]); ok && len(args) == 1 {
+		player.LookDirection(d)
+		return
+	}
+	if strings.ToLower(args[0]) == "in" && len(args) > 1 {
+		player.LookIn(strings.Join(args[1:], " "))
+		return
+	}
+	player.LookAt(strings.Join(args, " "))
+}
+
+func (p *Plugin) handleExamine(player *mud.Player, args []string) {
+	player.Examine(strings.Join(args, " "))
+}
+
+func (p *Plugin) handleSit(player *mud.Player) {
+	player.Sit()
+}
+
+func (p *Plugin) handleRest(player *mud.Player) {
+	player.Rest()
+}
+
+func (p *Plugin) handleStand(player *mud.Player) {
+	player.Stand()
 }
 
 func (p *Plugin) handleSleep(player *mud.Player) {
@@ -115,13 +246,199 @@ func (p *Plugin) handleShout(player *mud.Player, args []string) {
 	player.Shout(message)
 }
 
+func (p *Plugin) handleTalk(player *mud.Player, args []string) {
+	player.Talk(strings.Join(args, " "))
+}
+
+func (p *Plugin) handleAsk(player *mud.Player, args []string) {
+	npc, subject, ok := splitArgs(args, "about")
+	if !ok {
+		player.Notify("Ask whom about what? Example: ask captain about forest")
+		return
+	}
+	player.Ask(npc, subject)
+}
+
+func (p *Plugin) handleQuest(player *mud.Player, args []string) {
+	if len(args) == 0 {
+		player.ShowQuests()
+		return
+	}
+
+	quest := strings.Join(args[1:], " ")
+	switch strings.ToLower(args[0]) {
+	case "list":
+		player.ShowQuests()
+	case "info":
+		if quest == "" {
+			player.Notify("Which quest? Example: quest info 1")
+			return
+		}
+		player.QuestInfo(quest)
+	case "abandon":
+		if quest == "" {
+			player.Notify("Which quest? Example: quest abandon 1")
+			return
+		}
+		player.AbandonQuest(quest)
+	default:
+		player.Notify("Use quest list, quest info [quest] or quest abandon [quest].")
+	}
+}
+
 func (p *Plugin) handleKill(player *mud.Player, args []string) {
 	objective := strings.Join(args, " ")
 	player.Kill(objective)
 }
 
+func (p *Plugin) handleCast(player *mud.Player, args []string) {
+	if len(args) == 0 {
+		player.Notify("Which spell? Example: cast resurrect alice")
+		return
+	}
+	player.Cast(args[0], strings.Join(args[1:], " "))
+}
+
+func (p *Plugin) handleDeaths(player *mud.Player) {
+	player.ShowDeaths()
+}
+
+func (p *Plugin) handlePvP(player *mud.Player, args []string) {
+	if len(args) == 0 {
+		player.ShowPvP()
+		return
+	}
+	switch strings.ToLower(args[0]) {
+	case "on":
+		player.SetPvP(true)
+	case "off":
+		player.SetPvP(false)
+	default:
+		player.Notify("Use pvp on or pvp off.")
+	}
+}
+
+func (p *Plugin) handleFollow(player *mud.Player, args []string) {
+	name := strings.Join(args, " ")
+	if name == "" {
+		player.Notify("Follow whom? Example: follow alice")
+		return
+	}
+	player.Follow(name)
+}
+
+func (p *Plugin) handleGroup(player *mud.Player, args []string) {
+	if len(args) == 0 {
+		player.ShowGroup()
+		return
+	}
+	player.AddToGroup(strings.Join(args, " "))
+}
+
+func (p *Plugin) handleUngroup(player *mud.Player, args []string) {
+	player.Ungroup(strings.Join(args, " "))
+}
+
+func (p *Plugin) handleGroupTell(player *mud.Player, args []string) {
+	message := strings.Join(args, " ")
+	if message == "" {
+		player.Notify("Tell your group what? Example: gtell Let's go north")
+		return
+	}
+	player.GroupTell(message)
+}
+
 func (p *Plugin) handleStatus(player *mud.Player) {
-	player.Notify(fmt.Sprintf("%d/%d HP", player.CurrentHP, player.MaxHP))
+	player.Notify(player.ShowStatus())
+}
+
+func (p *Plugin) handleScore(player *mud.Player) {
+	player.ShowScore()
+}
+
+func (p *Plugin) handlePrompt(player *mud.Player, args []string) {
+	if len(args) == 0 {
+		player.SetPrompt(!player.Prompt)
+		return
+	}
+	switch strings.ToLower(args[0]) {
+	case "on":
+		player.SetPrompt(true)
+	case "off":
+		player.SetPrompt(false)
+	default:
+		player.Notify("Use prompt on or prompt off.")
+	}
+}
+
+func (p *Plugin) handleList(player *mud.Player) {
+	player.ListShop()
+}
+
+func (p *Plugin) handleBuy(player *mud.Player, args []string) {
+	player.Buy(strings.Join(args, " "))
+}
+
+func (p *Plugin) handleSell(player *mud.Player, args []string) {
+	player.Sell(strings.Join(args, " "))
+}
+
+func (p *Plugin) handleValue(player *mud.Player, args []string) {
+	player.Value(strings.Join(args, " "))
+}
+
+func (p *Plugin) handleAreas(player *mud.Player) {
+	player.Notify(p.world.ShowAreas())
+}
+
+func (p *Plugin) handleInventory(player *mud.Player) {
+	player.ShowInventory()
+}
+
+func (p *Plugin) handleGet(player *mud.Player, args []string) {
+	if item, container, ok := splitArgs(args, "from"); ok {
+		player.GetFrom(item, container)
+		return
+	}
+	player.Get(strings.Join(args, " "))
+}
+
+func (p *Plugin) handlePut(player *mud.Player, args []string) {
+	item, container, ok := splitArgs(args, "in")
+	if !ok {
+		player.Notify("Put what in what? Example: put bread in bag")
+		return
+	}
+	player.Put(item, container)
+}
+
+func (p *Plugin) handleDrop(player *mud.Player, args []string) {
+	player.Drop(strings.Join(args, " "))
+}
+
+func (p *Plugin) handleWear(player *mud.Player, args []string) {
+	player.Wear(strings.Join(args, " "))
+}
+
+func (p *Plugin) handleRemove(player *mud.Player, args []string) {
+	player.Remove(strings.Join(args, " "))
+}
+
+func (p *Plugin) handleConsume(player *mud.Player, verb string, args []string) {
+	player.Consume(verb, strings.Join(args, " "))
+}
+
+func (p *Plugin) handleDoor(player *mud.Player, args []string, doorAction func(d mud.Direction), containerAction func(keyword string)) {
+	target := strings.Join(args, " ")
+	if target == "" {
+		player.Notify("Which door or container? Example: open north, open chest")
+		return
+	}
+	if d, ok := parseDirection(target); ok {
+		doorAction(d)
+		return
+	}
+	containerAction(target)
 }
 
 func (p *Plugin) handleHelp(player *mud.Player) {
@@ -132,6 +449,66 @@ func (p *Plugin) handleDefault(player *mud.Player) {
 	player.Notify("I do not understand what you say. Type `help` if you want to check all the available commands.")
 }
 
+// splitArgs splits the arguments on the first appearance of the separator word, like the about of ask captain about forest.
+// Returns false if the separator is not found between other words.
+func splitArgs(args []string, separator string) (string, string, bool) {
+	for i, arg := range args {
+		if strings.EqualFold(arg, separator) && i > 0 && i < len(args)-1 {
+			return strings.Join(args[:i], " "), strings.Join(args[i+1:], " "), true
+		}
+	}
+	return "", "", false
+}
+
+// parseDirection returns the direction named by the player, accepting the same abbreviations as the movement commands
+func parseDirection(s string) (mud.Direction, bool) {
+	switch strings.ToLower(s) {
+	case "n":
+		return mud.North, true
+	case "s":
+		return mud.South, true
+	case "e":
+		return mud.East, true
+	case "w":
+		return mud.West, true
+	case "u":
+		return mud.Up, true
+	case "d":
+		return mud.Down, true
+	}
+	return mud.DirectionFromString(strings.ToLower(s))
+}
+
+// handleReloadUpload reloads the areas attached to the post by a system admin
+func (p *Plugin) handleReloadUpload(post *model.Post) {
+	if !p.API.HasPermissionTo(post.UserId, model.PERMISSION_MANAGE_SYSTEM) {
+		p.world.Notify(post.UserId, "Only system admins can reload the world.")
+		return
+	}
+
+	files := make(map[string][]byte)
+	for _, fileID := range post.FileIds {
+		info, appErr := p.API.GetFileInfo(fileID)
+		if appErr != nil {
+			p.world.Notify(post.UserId, "Cannot read the attached file: "+appErr.Error())
+			return
+		}
+		content, appErr := p.API.GetFile(fileID)
+		if appErr != nil {
+			p.world.Notify(post.UserId, "Cannot read the attached file: "+appErr.Error())
+			return
+		}
+		files[info.Name] = content
+	}
+
+	reloaded, err := p.world.ReloadAreaFiles(files)
+	if err != nil {
+		p.world.Notify(post.UserId, "There has been an error reloading the world: "+err.Error())
+		return
+	}
+	p.world.Notify(post.UserId, "Reloaded areas: "+strings.Join(reloaded, ", "))
+}
+
 func (p *Plugin) welcome(userID string) {
 	player, err := p.world.GetPlayer(userID)
 	if err != nil {
@@ -11,6 +11,8 @@ func getHelp() string {
 	return `Mattermud is the Multi-user dungeon integrated in Mattermost. The commands available are:
 	start: Creates a player for you and starts the game
 	help: Shows this help text
+	reload [area_id...]: Reloads the given areas, or all of them, from the plugin files. Only for system admins.
+		To reload an area from your own file, send it to the GM as an attachment with the message: reload
 
 ` + getIngameHelp()
 }
@@ -21,7 +23,7 @@ func getCommand() *model.Command {
 		DisplayName:      "Mattermud",
 		Description:      "Create a new mattermud player.",
 		AutoComplete:     true,
-		AutoCompleteDesc: "Available commands: help, start",
+		AutoCompleteDesc: "Available commands: help, start, reload",
 		AutoCompleteHint: "[command]",
 	}
 }
@@ -56,6 +58,15 @@ func (p *Plugin) ExecuteCommand(c *plugin.Context, args *model.CommandArgs) (*mo
 		}
 		p.welcome(args.UserId)
 		return getCommandResponse(model.COMMAND_RESPONSE_TYPE_EPHEMERAL, "Welcome to mattermud. The GM just messaged you to start the game."), nil
+	case "reload":
+		if !p.API.HasPermissionTo(args.UserId, model.PERMISSION_MANAGE_SYSTEM) {
+			return getCommandResponse(model.COMMAND_RESPONSE_TYPE_EPHEMERAL, "Only system admins can reload the world."), nil
+		}
+		reloaded, err := p.world.ReloadAreas(stringArgs[2:])
+		if err != nil {
+			return getCommandResponse(model.COMMAND_RESPONSE_TYPE_EPHEMERAL, "There has been an error reloading the world: "+err.Error()), nil
+		}
+		return getCommandResponse(model.COMMAND_RESPONSE_TYPE_EPHEMERAL, "Reloaded areas: "+strings.Join(reloaded, ", ")), nil
 	default:
 		return getCommandResponse(model.COMMAND_RESPONSE_TYPE_EPHEMERAL, getHelp()), nil
 	}
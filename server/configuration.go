@@ -2,7 +2,10 @@ package main
 
 import (
 	"reflect"
+	"strconv"
+	"time"
 
+	"github.com/mattermost/mattermost-plugin-mattermud/server/mud"
 	"github.com/pkg/errors"
 )
 
@@ -18,6 +21,14 @@ import (
 // If you add non-reference types to your configuration struct, be sure to rewrite Clone as a deep
 // copy appropriate for your types.
 type configuration struct {
+	// DefaultRoom is the ID of the room where new players start
+	DefaultRoom string
+	// DeathExperienceLoss is the percentage of the experience earned since the last level that players lose when dying
+	DeathExperienceLoss string
+	// DeathKeepInventory lets players keep their inventory when dying, instead of leaving it on their corpse
+	DeathKeepInventory bool
+	// DeathWeaknessMinutes is how many minutes players stay weakened after coming back to life
+	DeathWeaknessMinutes string
 }
 
 // Clone shallow copies the configuration. Your implementation may require a deep copy if
@@ -78,6 +89,24 @@ func (p *Plugin) OnConfigurationChange() error {
 	}
 
 	p.setConfiguration(configuration)
+	p.world.SetConfig(configuration.worldConfig())
 
 	return nil
 }
+
+// worldConfig returns the settings that affect the game world
+func (c *configuration) worldConfig() mud.Config {
+	death := mud.DefaultDeathPenalties
+	if loss, err := strconv.Atoi(c.DeathExperienceLoss); err == nil && loss >= 0 && loss <= 100 {
+		death.ExperienceLoss = loss
+	}
+	death.CorpseRun = !c.DeathKeepInventory
+	if minutes, err := strconv.Atoi(c.DeathWeaknessMinutes); err == nil && minutes >= 0 {
+		death.Weakness = time.Duration(minutes) * time.Minute
+	}
+
+	return mud.Config{
+		DefaultRoom: c.DefaultRoom,
+		Death:       death,
+	}
+}
@@ -28,7 +28,40 @@ const manifestStr = `
   "settings_schema": {
     "header": "",
     "footer": "",
-    "settings": []
+    "settings": [
+      {
+        "key": "DefaultRoom",
+        "display_name": "Default room",
+        "type": "text",
+        "help_text": "ID of the room where new players start, like midgaard_temple. If empty, the recall room of the area flagged as start is used.",
+        "placeholder": "",
+        "default": ""
+      },
+      {
+        "key": "DeathExperienceLoss",
+        "display_name": "Experience lost on death",
+        "type": "text",
+        "help_text": "Percentage of the experience earned since the last level that players lose when dying. Players never lose levels. Use 0 to disable it.",
+        "placeholder": "",
+        "default": "10"
+      },
+      {
+        "key": "DeathKeepInventory",
+        "display_name": "Keep inventory on death",
+        "type": "bool",
+        "help_text": "When true, players keep their inventory when dying. When false, they leave it on their corpse and have to go back for it before it rots away.",
+        "placeholder": "",
+        "default": false
+      },
+      {
+        "key": "DeathWeaknessMinutes",
+        "display_name": "Weakness after death (minutes)",
+        "type": "text",
+        "help_text": "How many minutes players stay weakened after coming back to life. Use 0 to disable it.",
+        "placeholder": "",
+        "default": "5"
+      }
+    ]
   }
 }
 `
@@ -0,0 +1,249 @@
+package mud
+
+import (
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"strings"
+)
+
+// Rarity denotes how uncommon a rolled item is, which sets how many affixes it gets and how much it is worth
+type Rarity int
+
+const (
+	// Common items have no affixes. It is the rarity of every item not rolled
+	Common Rarity = iota
+	// Uncommon items have one affix
+	Uncommon
+	// Rare items have two affixes
+	Rare
+	// Epic items have three affixes
+	Epic
+)
+
+// rarityNames maps each rarity to the name used on the JSON files and shown to the players
+var rarityNames = map[Rarity]string{
+	Common:   "common",
+	Uncommon: "uncommon",
+	Rare:     "rare",
+	Epic:     "epic",
+}
+
+// rarityChances is the chance of rolling each rarity, as x out of 10000
+var rarityChances = map[Rarity]int{
+	Common:   6000,
+	Uncommon: 2500,
+	Rare:     1200,
+	Epic:     300,
+}
+
+// rarityAffixes is how many affixes the items of each rarity get
+var rarityAffixes = map[Rarity]int{
+	Common:   0,
+	Uncommon: 1,
+	Rare:     2,
+	Epic:     3,
+}
+
+// rarityValue is the percentage of the value of the template the items of each rarity are worth
+var rarityValue = map[Rarity]int{
+	Common:   100,
+	Uncommon: 150,
+	Rare:     250,
+	Epic:     400,
+}
+
+// MarshalText marshals the rarity into its name
+func (r Rarity) MarshalText() ([]byte, error) {
+	name, ok := rarityNames[r]
+	if !ok {
+		return nil, fmt.Errorf("unknown rarity %d", r)
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText unmarshals the rarity from its name
+func (r *Rarity) UnmarshalText(b []byte) error {
+	for k, v := range rarityNames {
+		if v == string(b) {
+			*r = k
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown rarity %s", string(b))
+}
+
+// String returns the name of the rarity
+func (r Rarity) String() string {
+	return rarityNames[r]
+}
+
+// Affix is a random property an item can get when rolled, as defined on assets/affixes.json
+type Affix struct {
+	// ID is the unique identifier of the affix
+	ID string `json:"id"`
+	// Name is added to the name of the item, like sharp or of Seeing
+	Name string `json:"name"`
+	// Suffix denotes that the name goes after the name of the item instead of before. Items get at most one suffix
+	Suffix bool `json:"suffix"`
+	// Slots lists the equipment slots of the items that can get the affix. Empty means all of them
+	Slots []EquipmentSlot `json:"slots"`
+	// MinRarity is the lowest rarity of the items that can get the affix
+	MinRarity Rarity `json:"min_rarity"`
+	// Attack denotes how much attack the affix adds to the item
+	Attack int `json:"attack"`
+	// StatsModifiers denotes how much the affix adds to each stat modifier of the item
+	StatsModifiers Stats `json:"stats_modifiers"`
+	// MagicEffects are the magical effects the affix adds to the item, like seeing invisible things
+	MagicEffects EffectList `json:"magic_effects"`
+}
+
+// fits returns whether an item worn on the slot can get the affix
+func (a *Affix) fits(slot EquipmentSlot) bool {
+	if len(a.Slots) == 0 {
+		return true
+	}
+	for _, s := range a.Slots {
+		if s == slot {
+			return true
+		}
+	}
+	return false
+}
+
+// hasStatsModifiers returns whether the affix modifies any stat
+func (a *Affix) hasStatsModifiers() bool {
+	for _, modifier := range a.StatsModifiers {
+		if modifier != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadAffixes loads all the affixes defined on assets/affixes.json
+func (w *World) LoadAffixes(bundlePath string) error {
+	affixes, err := readAffixes(bundlePath)
+	if err != nil {
+		return err
+	}
+
+	w.affixesDB = affixes
+	return nil
+}
+
+// readAffixes reads the affixes defined on assets/affixes.json without adding them to the world
+func readAffixes(bundlePath string) ([]*Affix, error) {
+	var affixes []*Affix
+	if err := decodeAssetFile(filepath.Join(bundlePath, "assets", "affixes.json"), &affixes); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, a := range affixes {
+		if seen[a.ID] {
+			return nil, fmt.Errorf("affix ID %s duplicated", a.ID)
+		}
+		seen[a.ID] = true
+	}
+	return affixes, nil
+}
+
+// affixesFor returns the affixes an item worn on the slot can get
+func affixesFor(affixes []*Affix, slot EquipmentSlot) []*Affix {
+	fitting := []*Affix{}
+	for _, a := range affixes {
+		if a.fits(slot) {
+			fitting = append(fitting, a)
+		}
+	}
+	return fitting
+}
+
+// Roll creates a new item using another item as template. Templates with random affixes roll a rarity and get
+// that many affixes, which change the equipment, the name and the value of the new item
+func (i *Item) Roll() *Item {
+	newItem := i.Spawn()
+	if !i.RandomAffixes || i.Equipment == nil {
+		return newItem
+	}
+	rarity := rollRarity(rand.Intn(10000))
+	newItem.applyAffixes(rarity, pickAffixes(rarity, i.affixes, rand.Perm(len(i.affixes))))
+	return newItem
+}
+
+// rollRarity returns the rarity matching the roll, a number out of 10000
+func rollRarity(roll int) Rarity {
+	for r := Epic; r > Common; r-- {
+		if roll < rarityChances[r] {
+			return r
+		}
+		roll -= rarityChances[r]
+	}
+	return Common
+}
+
+// pickAffixes returns as many of the affixes as the rarity grants, going through them in the order of the permutation.
+// Affixes of a higher rarity are skipped, and so is any suffix after the first one
+func pickAffixes(rarity Rarity, affixes []*Affix, order []int) []*Affix {
+	picked := []*Affix{}
+	hasSuffix := false
+	for _, k := range order {
+		if len(picked) == rarityAffixes[rarity] {
+			break
+		}
+		a := affixes[k]
+		if a.MinRarity > rarity || (a.Suffix && hasSuffix) {
+			continue
+		}
+		hasSuffix = hasSuffix || a.Suffix
+		picked = append(picked, a)
+	}
+	return picked
+}
+
+// applyAffixes sets the rarity of the item and adds the affixes to its equipment and its name
+func (i *Item) applyAffixes(rarity Rarity, affixes []*Affix) {
+	i.Rarity = rarity
+	i.Affixes = affixes
+	i.Value = i.Value * rarityValue[rarity] / 100
+
+	equipment := i.Equipment.spawn()
+	for _, a := range affixes {
+		equipment.Attack += a.Attack
+		for s, modifier := range a.StatsModifiers {
+			equipment.StatsModifiers[s] += modifier
+		}
+		equipment.MagicEffects = append(equipment.MagicEffects, a.MagicEffects...)
+	}
+	i.Equipment = equipment
+	i.Name = affixedName(i.Name, affixes)
+}
+
+// affixedName returns the name of an item with the names of the affixes around it, fixing the indefinite article.
+// Example: a rusty sword with sharp and of Seeing becomes a sharp rusty sword of Seeing
+func affixedName(name string, affixes []*Affix) string {
+	prefixes, suffixes := []string{}, []string{}
+	for _, a := range affixes {
+		if a.Suffix {
+			suffixes = append(suffixes, a.Name)
+			continue
+		}
+		prefixes = append(prefixes, a.Name)
+	}
+	if len(prefixes) > 0 {
+		prefix := strings.Join(prefixes, " ")
+		words := strings.SplitN(name, " ", 2)
+		switch {
+		case len(words) < 2:
+			name = prefix + " " + name
+		case words[0] == "a" || words[0] == "an":
+			name = indefiniteArticle(prefix) + " " + prefix + " " + words[1]
+		case words[0] == "the" || words[0] == "some":
+			name = words[0] + " " + prefix + " " + words[1]
+		default:
+			name = prefix + " " + name
+		}
+	}
+	return strings.Join(append([]string{name}, suffixes...), " ")
+}
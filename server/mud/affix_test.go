@@ -0,0 +1,109 @@
+package mud
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollRarity(t *testing.T) {
+	tests := []struct {
+		roll     int
+		expected Rarity
+	}{
+		{0, Epic},
+		{299, Epic},
+		{300, Rare},
+		{1499, Rare},
+		{1500, Uncommon},
+		{3999, Uncommon},
+		{4000, Common},
+		{9999, Common},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, rollRarity(tt.roll), "roll %d", tt.roll)
+	}
+}
+
+func TestPickAffixes(t *testing.T) {
+	sharp := &Affix{ID: "sharp", Name: "sharp", Attack: 2}
+	bull := &Affix{ID: "of_the_bull", Name: "of the Bull", Suffix: true}
+	seeing := &Affix{ID: "of_seeing", Name: "of Seeing", Suffix: true, MinRarity: Rare}
+	affixes := []*Affix{sharp, bull, seeing}
+
+	tests := []struct {
+		name     string
+		rarity   Rarity
+		order    []int
+		expected []*Affix
+	}{
+		{"common gets none", Common, []int{0, 1, 2}, []*Affix{}},
+		{"uncommon gets one", Uncommon, []int{1, 0, 2}, []*Affix{bull}},
+		{"too rare affixes are skipped", Uncommon, []int{2, 0, 1}, []*Affix{sharp}},
+		{"only one suffix", Epic, []int{1, 2, 0}, []*Affix{bull, sharp}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, pickAffixes(tt.rarity, affixes, tt.order))
+		})
+	}
+}
+
+func TestApplyAffixes(t *testing.T) {
+	template := &Item{
+		ID:        "rusty_sword",
+		Name:      "a rusty sword",
+		Value:     20,
+		Equipment: &Equipment{Slot: RightHand, Attack: 3, StatsModifiers: Stats{Strength: 1}},
+	}
+	affixes := []*Affix{
+		{ID: "of_seeing", Name: "of Seeing", Suffix: true, MagicEffects: EffectList{{SeeInvisible: true}}},
+		{ID: "ugly", Name: "ugly", StatsModifiers: Stats{Strength: 2}},
+		{ID: "sharp", Name: "sharp", Attack: 2},
+	}
+
+	item := template.Spawn()
+	item.applyAffixes(Rare, affixes)
+
+	assert.Equal(t, "an ugly sharp rusty sword of Seeing", item.Name)
+	assert.Equal(t, 50, item.Value)
+	assert.Equal(t, 5, item.Equipment.GetAttack())
+	assert.Equal(t, 3, item.Equipment.GetStat(Strength))
+	assert.True(t, item.Equipment.CanSeeInvisible())
+	assert.Contains(t, item.Examine(), "Rarity: rare")
+
+	assert.Equal(t, "a rusty sword", template.Name)
+	assert.Equal(t, 3, template.Equipment.GetAttack())
+	assert.Equal(t, 1, template.Equipment.GetStat(Strength))
+	assert.False(t, template.Equipment.CanSeeInvisible())
+}
+
+func TestAffixedName(t *testing.T) {
+	sharp := &Affix{Name: "sharp"}
+	ancient := &Affix{Name: "ancient"}
+	seeing := &Affix{Name: "of Seeing", Suffix: true}
+
+	assert.Equal(t, "a sharp rusty sword", affixedName("a rusty sword", []*Affix{sharp}))
+	assert.Equal(t, "an ancient rusty sword of Seeing", affixedName("a rusty sword", []*Affix{ancient, seeing}))
+	assert.Equal(t, "some sharp daggers", affixedName("some daggers", []*Affix{sharp}))
+	assert.Equal(t, "Excalibur of Seeing", affixedName("Excalibur", []*Affix{seeing}))
+}
+
+func TestRolledItemsArePersisted(t *testing.T) {
+	item := &Item{ID: "rusty_sword", Name: "a rusty sword", Equipment: &Equipment{Slot: RightHand, Attack: 3}}
+	item.applyAffixes(Uncommon, []*Affix{{ID: "sharp", Name: "sharp", Attack: 2}})
+
+	saved := playerToJSONPlayer(&Player{Race: testHuman, Class: testWarrior, Inventory: ItemList{item}, CurrentRoom: &Room{ID: "temple"}})
+	b, err := json.Marshal(saved)
+	require.NoError(t, err)
+
+	var loaded JSONPlayer
+	require.NoError(t, json.Unmarshal(b, &loaded))
+	require.Len(t, loaded.Inventory, 1)
+	assert.Equal(t, "a sharp rusty sword", loaded.Inventory[0].Name)
+	assert.Equal(t, Uncommon, loaded.Inventory[0].Rarity)
+	assert.Equal(t, "sharp", loaded.Inventory[0].Affixes[0].ID)
+	assert.Equal(t, 5, loaded.Inventory[0].Equipment.GetAttack())
+}
@@ -0,0 +1,64 @@
+package mud
+
+import (
+	"strings"
+)
+
+// Appearance contains how a character is named and described to the players
+type Appearance struct {
+	// Name is the name shown to the player, without the article. Example: cute bunny
+	Name string `json:"name"`
+	// Article is the indefinite article used before the name. Defaults to "a" or "an" depending on the name
+	Article string `json:"article"`
+	// Proper denotes that the name is a proper noun, so it is never preceded by an article. Example: Hassan
+	Proper bool `json:"proper"`
+	// Gender is used to choose the pronouns referring to the character: neutral, male or female. Defaults to neutral
+	Gender Gender `json:"gender"`
+	// ShortDescription is the line shown when the character is in the room. Example: A cute bunny is nibbling some grass here.
+	ShortDescription string `json:"short_description"`
+	// Description is shown to the player when looking at the character
+	Description string `json:"description"`
+	// Keywords are the words the player can use to refer to the character, besides its name
+	Keywords []string `json:"keywords"`
+}
+
+// IndefiniteName returns the name preceded by its indefinite article. Example: a cute bunny
+func (a *Appearance) IndefiniteName() string {
+	if a.Proper {
+		return a.Name
+	}
+	article := a.Article
+	if article == "" {
+		article = indefiniteArticle(a.Name)
+	}
+	return article + " " + a.Name
+}
+
+// DefiniteName returns the name preceded by the definite article. Example: the cute bunny
+func (a *Appearance) DefiniteName() string {
+	if a.Proper {
+		return a.Name
+	}
+	return "the " + a.Name
+}
+
+// HasKeyword returns whether the player may refer to the character by keyword, either its name or one of its keywords
+func (a *Appearance) HasKeyword(keyword string) bool {
+	if strings.EqualFold(keyword, a.Name) {
+		return true
+	}
+	for _, v := range a.Keywords {
+		if strings.EqualFold(keyword, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Show returns the line shown when the character is in the room
+func (a *Appearance) Show() string {
+	if a.ShortDescription != "" {
+		return a.ShortDescription
+	}
+	return capitalize(a.IndefiniteName()) + " is here."
+}
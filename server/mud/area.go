@@ -0,0 +1,305 @@
+package mud
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// AreaFlagStart marks the area whose recall room is the default room of the world
+	AreaFlagStart = "start"
+	// AreaFlagArena marks the areas where players can always fight each other, and lose nothing when defeated
+	AreaFlagArena = "arena"
+)
+
+// knownAreaFlags lists all the flags that an area may have
+var knownAreaFlags = map[string]bool{
+	AreaFlagStart: true,
+	AreaFlagArena: true,
+}
+
+// Area stores the information shared by all the rooms of an area
+type Area struct {
+	// ID is the unique identifier for this area
+	ID string
+	// Name shown to the user
+	Name string
+	// Author is who wrote the area
+	Author string
+	// MinLevel is the lowest recommended level to visit the area
+	MinLevel int
+	// MaxLevel is the highest recommended level to visit the area
+	MaxLevel int
+	// RecallRoom is the room players are sent to when recalling from this area
+	RecallRoom *Room
+	// ResetInterval is how often the area is reset
+	ResetInterval time.Duration
+	// Flags lists all the flags of the area
+	Flags []string
+	// Resets lists all the rules run when the area is reset
+	Resets []*Reset
+	// rooms lists all the rooms of the area
+	rooms []*Room
+	// lastReset tells when the area was reset for the last time
+	lastReset time.Time
+}
+
+// HasFlag returns whether the area has certain flag
+func (a *Area) HasFlag(flag string) bool {
+	for _, v := range a.Flags {
+		if v == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// Show returns the line describing the area on the area list
+func (a *Area) Show() string {
+	message := a.Name
+	switch {
+	case a.MinLevel > 0 && a.MaxLevel > 0:
+		message += fmt.Sprintf(" (levels %d-%d)", a.MinLevel, a.MaxLevel)
+	case a.MinLevel > 0:
+		message += fmt.Sprintf(" (levels %d+)", a.MinLevel)
+	}
+	if a.Author != "" {
+		message += " by " + a.Author
+	}
+	return message
+}
+
+// jsonAreaToArea converts an imported json area to an usable Area in the game. The rooms and resets are resolved once all the rooms are loaded.
+func jsonAreaToArea(in *JSONArea) (*Area, error) {
+	for _, flag := range in.Flags {
+		if !knownAreaFlags[flag] {
+			return nil, fmt.Errorf("unknown flag %s for area %s", flag, in.ID)
+		}
+	}
+	if in.MaxLevel > 0 && in.MinLevel > in.MaxLevel {
+		return nil, fmt.Errorf("min level higher than max level for area %s", in.ID)
+	}
+
+	name := in.Name
+	if name == "" {
+		name = in.ID
+	}
+
+	resetInterval := time.Duration(in.ResetInterval) * time.Minute
+	if resetInterval == 0 {
+		resetInterval = DefaultResetInterval
+	}
+
+	return &Area{
+		ID:            in.ID,
+		Name:          name,
+		Author:        in.Author,
+		MinLevel:      in.MinLevel,
+		MaxLevel:      in.MaxLevel,
+		ResetInterval: resetInterval,
+		Flags:         in.Flags,
+	}, nil
+}
+
+// ShowAreas returns the list of all the areas in the world
+func (w *World) ShowAreas() string {
+	areas := make([]*Area, 0, len(w.areas))
+	for _, a := range w.areas {
+		areas = append(areas, a)
+	}
+	sort.Slice(areas, func(i, j int) bool {
+		if areas[i].MinLevel != areas[j].MinLevel {
+			return areas[i].MinLevel < areas[j].MinLevel
+		}
+		return areas[i].Name < areas[j].Name
+	})
+
+	lines := []string{"Areas:"}
+	for _, a := range areas {
+		lines = append(lines, "* "+a.Show())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// buildAreas converts the json areas into areas of the world using the templates, replacing any area with the same ID
+// and the templates of the world. The world is only modified if all the areas are valid.
+func (w *World) buildAreas(jsonAreas []*JSONArea, t *templates) error {
+	newAreas := make(map[string]*Area)
+	jsonRooms := make(map[string]*JSONRoom)
+	for _, in := range jsonAreas {
+		if _, ok := newAreas[in.ID]; ok {
+			return fmt.Errorf("area id '%s' duplicated", in.ID)
+		}
+		a, err := jsonAreaToArea(in)
+		if err != nil {
+			return err
+		}
+		newAreas[in.ID] = a
+
+		for _, v := range in.Rooms {
+			v.ID = in.ID + "_" + v.ID
+			v.AreaID = in.ID
+			if _, ok := jsonRooms[v.ID]; ok {
+				return fmt.Errorf("room id '%s' duplicated", v.ID)
+			}
+			jsonRooms[v.ID] = v
+		}
+	}
+
+	// Rooms and areas that are not being replaced are kept as they are
+	rooms := make(map[string]*Room)
+	for id, room := range w.rooms {
+		if _, ok := newAreas[room.AreaID]; !ok {
+			rooms[id] = room
+		}
+	}
+	areas := make(map[string]*Area)
+	for id, a := range w.areas {
+		areas[id] = a
+	}
+
+	newRooms, err := jsonRoomsToRooms(jsonRooms, rooms)
+	if err != nil {
+		return err
+	}
+	for id, room := range newRooms {
+		rooms[id] = room
+		a := newAreas[room.AreaID]
+		a.rooms = append(a.rooms, room)
+		room.area = a
+	}
+
+	for _, in := range jsonAreas {
+		a := newAreas[in.ID]
+		recallRoom, ok := newRooms[in.ID+"_"+in.RecallRoom]
+		if !ok {
+			return fmt.Errorf("cannot find recall room %s for area %s", in.RecallRoom, in.ID)
+		}
+		a.RecallRoom = recallRoom
+
+		for _, v := range in.Resets {
+			reset, err := jsonResetToReset(v, in.ID, newRooms, t.mobs, t.items)
+			if err != nil {
+				return err
+			}
+			a.Resets = append(a.Resets, reset)
+		}
+
+		npcIDs := make(map[string]bool)
+		for _, n := range in.NPCs {
+			if npcIDs[n.ID] {
+				return fmt.Errorf("npc id '%s' duplicated on area %s", n.ID, in.ID)
+			}
+			npcIDs[n.ID] = true
+			if err := resolveNPC(n, in.ID, rooms, t.items, t.quests); err != nil {
+				return err
+			}
+		}
+		areas[in.ID] = a
+	}
+
+	defaultRoom, err := w.findDefaultRoom(areas, rooms)
+	if err != nil {
+		return err
+	}
+
+	links, err := linkTemplates(areas, rooms, newAreas, t)
+	if err != nil {
+		return err
+	}
+
+	for id, old := range w.areas {
+		if _, ok := newAreas[id]; ok {
+			w.retireArea(old, rooms, rooms[defaultRoom])
+		}
+	}
+	relinkRooms(rooms, newAreas)
+
+	for _, in := range jsonAreas {
+		for _, n := range in.NPCs {
+			n.CurrentRoom.NPCs = append(n.CurrentRoom.NPCs, n)
+		}
+	}
+
+	for _, link := range links {
+		link()
+	}
+	w.affixesDB = t.affixes
+	w.itemsDB = t.items
+	w.mobsDB = t.mobs
+	w.questsDB = t.quests
+
+	w.areas = areas
+	w.rooms = rooms
+	w.defaultRoom = defaultRoom
+	for _, p := range w.players {
+		p.DefaultRoom = w.rooms[w.defaultRoom]
+		w.relinkPlayerQuests(p)
+	}
+
+	for _, a := range newAreas {
+		a.Reset()
+	}
+
+	return nil
+}
+
+// retireArea removes all the contents of the area that is being replaced.
+// Players are moved to the new room with the same ID, or to the default room if it does not exist anymore.
+func (w *World) retireArea(old *Area, rooms map[string]*Room, defaultRoom *Room) {
+	retired := make(map[*Room]bool)
+	for _, room := range old.rooms {
+		retired[room] = true
+	}
+	w.endBattlesIn(retired)
+
+	for _, room := range old.rooms {
+		target, ok := rooms[room.ID]
+		if ok {
+			target.Items = append(target.Items, room.Items...)
+		} else {
+			target = defaultRoom
+		}
+
+		for id, p := range room.Players {
+			delete(room.Players, id)
+			p.CurrentRoom = target
+			target.Players[id] = p
+			if ok {
+				p.Notify("The world shimmers around you for a moment.")
+			} else {
+				p.Notify(fmt.Sprintf("The place you were at fades away, and you find yourself back at %s.", target.Name))
+			}
+		}
+
+		for _, m := range room.Mobs {
+			m.Despawn()
+		}
+		room.Mobs = MobList{}
+	}
+}
+
+// relinkRooms points the transitions and NPC teleports towards the replaced areas to the new rooms, removing the ones leading to rooms that do not exist anymore
+func relinkRooms(rooms map[string]*Room, replaced map[string]*Area) {
+	for _, room := range rooms {
+		if _, ok := replaced[room.AreaID]; ok {
+			continue
+		}
+		for _, n := range room.NPCs {
+			n.relink(rooms, replaced)
+		}
+		for d, door := range room.Neighbours {
+			if _, ok := replaced[door.room.AreaID]; !ok {
+				continue
+			}
+			newRoom, ok := rooms[door.room.ID]
+			if !ok || newRoom.AreaID != door.room.AreaID {
+				delete(room.Neighbours, d)
+				continue
+			}
+			door.room = newRoom
+		}
+	}
+}
@@ -12,10 +12,23 @@ const (
 	BattleTurnTime = 5 * time.Second
 )
 
-// Battle represents any battle between mobs and players
+// Team is one side of players on a battle
+type Team []*Player
+
+// Battle represents any battle between mobs and players, or between teams of players
 type Battle struct {
-	PlayerSide  []*Player
-	MobSide     []*Mob
+	// Teams are the sides of players. Battles against mobs have a single team, and battles between players have two
+	Teams []Team
+	// MobSide are the mobs fighting the players. Battles between players have no mobs
+	MobSide []*Mob
+	// playerTargets is the mob each player is attacking
+	playerTargets map[*Player]*Mob
+	// rivalTargets is the player of another team each player is attacking
+	rivalTargets map[*Player]*Player
+	// mobTargets is the player each mob is attacking
+	mobTargets map[*Mob]*Player
+	// killers is who dealt the last blow to each player killed in the current round
+	killers     map[*Player]string
 	lock        sync.Mutex
 	battleEnded chan struct{}
 }
@@ -45,40 +58,29 @@ func (b *Battle) Start() {
 			if b.finishBattle() {
 				return
 			}
-			hitNotifications := []string{}
-			killNotifications := []string{}
-			for _, p := range b.PlayerSide {
-				att := p.GetLeftAttack() + p.GetRightAttack()
-				mob := b.GetNextMob()
-				def := mob.GetCurrentDefense()
-				damage := max(att-def, 1)
-				mob.CurrentHP -= damage
-				hitNotifications = append(hitNotifications, fmt.Sprintf("%s inflicted %d damage to %s.", p.Name, damage, mob.ID))
-				if mob.CurrentHP <= 0 {
-					killNotifications = append(killNotifications, fmt.Sprintf("%s killed the %s!", p.Name, mob.ID))
-				}
-			}
-			for _, m := range b.MobSide {
-				att := m.GetAttack()
-				player := b.GetNextPlayer()
-				def := player.GetCurrentDefense()
-				damage := max(att-def, 1)
-				player.CurrentHP -= damage
-				hitNotifications = append(hitNotifications, fmt.Sprintf("The %s inflicted %d damage to %s.", m.ID, damage, player.Name))
-				if player.CurrentHP <= 0 {
-					killNotifications = append(killNotifications, fmt.Sprintf("The %s killed %s!", m.ID, player.Name))
-				}
-			}
-			b.NotifyAll(strings.Join(append(hitNotifications, killNotifications...), "\n"))
+			b.lock.Lock()
+			b.NotifyAll(strings.Join(b.round(globalDice{}), "\n"))
 			playersToRemove := []*Player{}
-			for _, p := range b.PlayerSide {
+			for _, p := range b.Players() {
 				if p.CurrentHP <= 0 {
 					playersToRemove = append(playersToRemove, p)
 				}
 			}
 			for _, p := range playersToRemove {
 				b.RemovePlayer(p)
-				p.Dead()
+				if b.IsPvP() {
+					p.Defeated(b.killers[p])
+				} else {
+					p.Dead(b.killers[p])
+				}
+				delete(b.killers, p)
+			}
+
+			if b.IsPvP() {
+				b.checkPvPEnd()
+				b.lock.Unlock()
+				time.Sleep(BattleTurnTime)
+				continue
 			}
 
 			mobsToRemove := []*Mob{}
@@ -90,24 +92,274 @@ func (b *Battle) Start() {
 			for _, m := range mobsToRemove {
 				b.RemoveMob(m)
 				m.Dead()
+				b.shareGold(m)
+				b.shareExperience(m)
+				for _, p := range b.Players() {
+					p.QuestEvent(ObjectiveKill, m.ID)
+				}
 			}
 
-			if len(b.PlayerSide) == 0 {
-				b.Stop()
+			fled := false
+			for _, m := range append([]*Mob{}, b.MobSide...) {
+				if m.ShouldFlee() && m.Flee() {
+					b.RemoveMob(m)
+					fled = true
+				}
+			}
+
+			if len(b.Players()) == 0 {
+				b.end()
 			}
 
 			if len(b.MobSide) == 0 {
-				for _, p := range b.PlayerSide {
-					p.IsFighting = false
+				if fled {
+					b.NotifyAll("There is no one left to fight.")
+				} else {
+					b.NotifyAll("You won!")
 				}
-				b.NotifyAll("You won!")
-				b.Stop()
+				b.end()
 			}
+			b.lock.Unlock()
 			time.Sleep(BattleTurnTime)
 		}
 	}()
 }
 
+// checkPvPEnd stops the battle between players once only one team is left standing
+func (b *Battle) checkPvPEnd() {
+	standing := []Team{}
+	for _, team := range b.Teams {
+		if len(team) > 0 {
+			standing = append(standing, team)
+		}
+	}
+	if len(standing) > 1 {
+		return
+	}
+	for _, team := range standing {
+		for _, p := range team {
+			p.Notify("You won!")
+		}
+	}
+	b.end()
+}
+
+// end stops the battle for good, leaving the players standing and dropping the items they queued for their next turn
+func (b *Battle) end() {
+	for _, p := range b.Players() {
+		p.Position = Standing
+		p.queued = nil
+	}
+	b.Stop()
+}
+
+// round runs the turns of every combatant in initiative order and returns the battle messages.
+// Combatants killed earlier in the round lose their turn, and nobody attacks them anymore.
+// Players who queued an item consume it instead of attacking, leaving the battle if it recalls them.
+func (b *Battle) round(dice Dice) []string {
+	combatants := []Combatant{}
+	for _, p := range b.Players() {
+		combatants = append(combatants, p)
+	}
+	for _, m := range b.MobSide {
+		combatants = append(combatants, m)
+	}
+
+	notifications := []string{}
+	for _, c := range InitiativeOrder(dice, combatants) {
+		switch c := c.(type) {
+		case *Player:
+			if c.CurrentHP <= 0 {
+				continue
+			}
+			if c.Position == Stunned {
+				c.Position = Fighting
+				notifications = append(notifications, fmt.Sprintf("%s is too stunned to fight back.", c.Name))
+				continue
+			}
+			if c.queued != nil {
+				room := c.CurrentRoom
+				if act := c.consumeQueued(); act != "" {
+					notifications = append(notifications, act)
+					if c.CurrentRoom != room {
+						b.RemovePlayer(c)
+					}
+					continue
+				}
+			}
+			for _, attack := range c.Attacks() {
+				if b.IsPvP() {
+					rival := b.RivalTarget(c)
+					if rival == nil {
+						break
+					}
+					notifications = append(notifications, strike(dice, c, rival, c.Name, rival.Name, &rival.CurrentHP, attack)...)
+					b.setKiller(rival, c.Name)
+					continue
+				}
+				mob := b.PlayerTarget(c)
+				if mob == nil {
+					break
+				}
+				notifications = append(notifications, strike(dice, c, mob, c.Name, mob.DefiniteName(), &mob.CurrentHP, attack)...)
+			}
+		case *Mob:
+			if c.CurrentHP <= 0 {
+				continue
+			}
+			for _, attack := range c.Attacks() {
+				player := b.MobTarget(c)
+				if player == nil {
+					break
+				}
+				notifications = append(notifications, strike(dice, c, player, capitalize(c.DefiniteName()), player.Name, &player.CurrentHP, attack)...)
+				b.setKiller(player, c.IndefiniteName())
+			}
+		}
+	}
+	return notifications
+}
+
+// setKiller records the attacker as the killer of the player, if the player has just died
+func (b *Battle) setKiller(p *Player, attacker string) {
+	if p.CurrentHP > 0 {
+		return
+	}
+	if b.killers == nil {
+		b.killers = make(map[*Player]string)
+	}
+	if _, ok := b.killers[p]; !ok {
+		b.killers[p] = attacker
+	}
+}
+
+// strike rolls one attack and takes the damage from the health points of the defender, returning the battle messages
+func strike(dice Dice, attacker, defender Combatant, attackerName, defenderName string, defenderHP *int, attack Attack) []string {
+	result := RollAttack(dice, attacker, defender, attack)
+	*defenderHP -= result.Damage
+	messages := []string{describeAttack(attackerName, defenderName, attack, result)}
+	if *defenderHP <= 0 {
+		messages = append(messages, fmt.Sprintf("%s killed %s!", attackerName, defenderName))
+	}
+	return messages
+}
+
+// PlayerTarget returns the mob the player is attacking. If it is dead or out of the battle, the player
+// turns to the next alive mob.
+func (b *Battle) PlayerTarget(p *Player) *Mob {
+	if m := b.playerTargets[p]; m != nil && m.CurrentHP > 0 && b.IsMobFighting(m) {
+		return m
+	}
+	m := b.GetNextMob()
+	b.setPlayerTarget(p, m)
+	return m
+}
+
+// MobTarget returns the player the mob is attacking. Bullies pick the weakest player on every attack.
+// The rest keep attacking the same player until it is dead or out of the battle, and then turn to the tank.
+func (b *Battle) MobTarget(m *Mob) *Player {
+	if m.HasFlag(MobFlagBully) {
+		return b.GetWeakestPlayer()
+	}
+	if p := b.mobTargets[m]; p != nil && p.CurrentHP > 0 && b.IsPlayerFighting(p) {
+		return p
+	}
+	p := b.GetTank()
+	b.setMobTarget(m, p)
+	return p
+}
+
+// RivalTarget returns the player of another team the player is attacking. If it is dead or out of the battle,
+// the player turns to the next alive rival.
+func (b *Battle) RivalTarget(p *Player) *Player {
+	if rival := b.rivalTargets[p]; rival != nil && rival.CurrentHP > 0 && b.AreRivals(p, rival) {
+		return rival
+	}
+	var next *Player
+	for _, rival := range b.Players() {
+		if rival.CurrentHP > 0 && b.AreRivals(p, rival) {
+			next = rival
+			break
+		}
+	}
+	b.setRivalTarget(p, next)
+	return next
+}
+
+// SwitchTarget makes the player attack the mob from now on
+func (b *Battle) SwitchTarget(p *Player, m *Mob) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.playerTargets[p] == m {
+		p.Notify(fmt.Sprintf("You are already fighting %s!", m.DefiniteName()))
+		return
+	}
+	b.setPlayerTarget(p, m)
+	p.Notify(fmt.Sprintf("You turn to attack %s!", m.DefiniteName()))
+	p.CurrentRoom.Act(p, fmt.Sprintf("%s turns to attack %s!", p.Name, m.DefiniteName()))
+}
+
+// SwitchRival makes the player attack a player of another team from now on
+func (b *Battle) SwitchRival(p, rival *Player) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.rivalTargets[p] == rival {
+		p.Notify(fmt.Sprintf("You are already fighting %s!", rival.Name))
+		return
+	}
+	b.setRivalTarget(p, rival)
+	p.Notify(fmt.Sprintf("You turn to attack %s!", rival.Name))
+	p.CurrentRoom.Act(p, fmt.Sprintf("%s turns to attack %s!", p.Name, rival.Name))
+}
+
+// Engage makes the player attack the mob, and the mob fight back if it was not fighting anyone else
+func (b *Battle) Engage(p *Player, m *Mob) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.setPlayerTarget(p, m)
+	if b.mobTargets[m] == nil {
+		b.setMobTarget(m, p)
+	}
+}
+
+// EngageRival makes the player attack a player of another team, and the rival fight back if it was not fighting anyone else
+func (b *Battle) EngageRival(p, rival *Player) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.setRivalTarget(p, rival)
+	if b.rivalTargets[rival] == nil {
+		b.setRivalTarget(rival, p)
+	}
+}
+
+// setPlayerTarget sets the mob the player attacks
+func (b *Battle) setPlayerTarget(p *Player, m *Mob) {
+	if b.playerTargets == nil {
+		b.playerTargets = make(map[*Player]*Mob)
+	}
+	b.playerTargets[p] = m
+}
+
+// setRivalTarget sets the player of another team the player attacks
+func (b *Battle) setRivalTarget(p, rival *Player) {
+	if b.rivalTargets == nil {
+		b.rivalTargets = make(map[*Player]*Player)
+	}
+	b.rivalTargets[p] = rival
+}
+
+// setMobTarget sets the player the mob attacks
+func (b *Battle) setMobTarget(m *Mob, p *Player) {
+	if b.mobTargets == nil {
+		b.mobTargets = make(map[*Mob]*Player)
+	}
+	b.mobTargets[m] = p
+}
+
 // GetNextMob returns the next alive mob in the list
 func (b *Battle) GetNextMob() *Mob {
 	for _, m := range b.MobSide {
@@ -120,7 +372,7 @@ func (b *Battle) GetNextMob() *Mob {
 
 // GetNextPlayer returns the next alive player in the list
 func (b *Battle) GetNextPlayer() *Player {
-	for _, p := range b.PlayerSide {
+	for _, p := range b.Players() {
 		if p.CurrentHP > 0 {
 			return p
 		}
@@ -128,30 +380,101 @@ func (b *Battle) GetNextPlayer() *Player {
 	return nil
 }
 
-// AddPlayer adds one player to the battle
+// GetWeakestPlayer returns the alive player with the fewest health points
+func (b *Battle) GetWeakestPlayer() *Player {
+	var weakest *Player
+	for _, p := range b.Players() {
+		if p.CurrentHP > 0 && (weakest == nil || p.CurrentHP < weakest.CurrentHP) {
+			weakest = p
+		}
+	}
+	return weakest
+}
+
+// GetTank returns the alive player best suited to take the blows: the one with the most defense,
+// or the most health points if there is a tie
+func (b *Battle) GetTank() *Player {
+	var tank *Player
+	for _, p := range b.Players() {
+		if p.CurrentHP <= 0 {
+			continue
+		}
+		if tank == nil || p.GetCurrentDefense() > tank.GetCurrentDefense() ||
+			(p.GetCurrentDefense() == tank.GetCurrentDefense() && p.CurrentHP > tank.CurrentHP) {
+			tank = p
+		}
+	}
+	return tank
+}
+
+// Players returns the players of all the teams of the battle
+func (b *Battle) Players() []*Player {
+	players := []*Player{}
+	for _, team := range b.Teams {
+		players = append(players, team...)
+	}
+	return players
+}
+
+// IsPvP returns whether the battle is between teams of players
+func (b *Battle) IsPvP() bool {
+	return len(b.Teams) > 1
+}
+
+// TeamOf returns the index of the team of the player, or -1 if the player is not fighting on this battle
+func (b *Battle) TeamOf(player *Player) int {
+	for i, team := range b.Teams {
+		for _, p := range team {
+			if p == player {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// AreRivals returns whether both players fight on the battle on different teams
+func (b *Battle) AreRivals(p, other *Player) bool {
+	team, otherTeam := b.TeamOf(p), b.TeamOf(other)
+	return team >= 0 && otherTeam >= 0 && team != otherTeam
+}
+
+// AddPlayer adds one player to the battle against the mobs
 func (b *Battle) AddPlayer(player *Player) {
+	b.AddPlayerToTeam(player, 0)
+}
+
+// AddPlayerToTeam adds one player to the battle on the given team
+func (b *Battle) AddPlayerToTeam(player *Player, team int) {
 	b.lock.Lock()
 	defer b.lock.Unlock()
 
-	for _, v := range b.PlayerSide {
-		if v == player {
-			return
-		}
+	if b.TeamOf(player) >= 0 {
+		return
+	}
+	for len(b.Teams) <= team {
+		b.Teams = append(b.Teams, Team{})
+	}
+	b.Teams[team] = append(b.Teams[team], player)
+	if !player.IsFighting() {
+		player.Position = Fighting
 	}
-	b.PlayerSide = append(b.PlayerSide, player)
-	player.IsFighting = true
 }
 
 // RemovePlayer removes one player from the battle
 func (b *Battle) RemovePlayer(player *Player) {
-	for i, v := range b.PlayerSide {
-		if v == player {
-			b.PlayerSide = append(b.PlayerSide[:i], b.PlayerSide[i+1:]...)
-			v.IsFighting = false
-			return
+	for t, team := range b.Teams {
+		for i, v := range team {
+			if v == player {
+				b.Teams[t] = append(team[:i], team[i+1:]...)
+				delete(b.playerTargets, v)
+				delete(b.rivalTargets, v)
+				v.Position = Standing
+				v.queued = nil
+				return
+			}
 		}
 	}
-	return
 }
 
 // AddMob adds one mob to the battle
@@ -172,6 +495,7 @@ func (b *Battle) RemoveMob(mob *Mob) {
 	for i, v := range b.MobSide {
 		if v == mob {
 			b.MobSide = append(b.MobSide[:i], b.MobSide[i+1:]...)
+			delete(b.mobTargets, v)
 			return
 		}
 	}
@@ -181,30 +505,46 @@ func (b *Battle) RemoveMob(mob *Mob) {
 // mergeBattles merge two battles
 func mergeBattles(a, b *Battle) *Battle {
 	newBattle := &Battle{
-		PlayerSide: []*Player{},
-		MobSide:    []*Mob{},
+		Teams:   []Team{},
+		MobSide: []*Mob{},
 	}
 
 	if a != nil {
 		a.lock.Lock()
 		defer a.lock.Unlock()
-		newBattle.PlayerSide = a.PlayerSide
+		newBattle.Teams = a.Teams
 		newBattle.MobSide = a.MobSide
+		newBattle.copyTargets(a)
 		a.Stop()
 	}
 
 	if b != nil {
 		b.lock.Lock()
 		defer b.lock.Unlock()
-	OUTER:
-		for _, in := range b.PlayerSide {
-			for _, present := range newBattle.PlayerSide {
+		for t, team := range b.Teams {
+			for len(newBattle.Teams) <= t {
+				newBattle.Teams = append(newBattle.Teams, Team{})
+			}
+		OUTER:
+			for _, in := range team {
+				for _, present := range newBattle.Players() {
+					if in == present {
+						continue OUTER
+					}
+				}
+				newBattle.Teams[t] = append(newBattle.Teams[t], in)
+			}
+		}
+	OUTERMOBS:
+		for _, in := range b.MobSide {
+			for _, present := range newBattle.MobSide {
 				if in == present {
-					continue OUTER
+					continue OUTERMOBS
 				}
 			}
-			newBattle.PlayerSide = append(newBattle.PlayerSide, in)
+			newBattle.MobSide = append(newBattle.MobSide, in)
 		}
+		newBattle.copyTargets(b)
 		b.Stop()
 	}
 
@@ -213,20 +553,49 @@ func mergeBattles(a, b *Battle) *Battle {
 	return newBattle
 }
 
+// copyTargets keeps the targets of the combatants of another battle, unless they already have one on this battle
+func (b *Battle) copyTargets(other *Battle) {
+	for p, m := range other.playerTargets {
+		if b.playerTargets[p] == nil {
+			b.setPlayerTarget(p, m)
+		}
+	}
+	for p, rival := range other.rivalTargets {
+		if b.rivalTargets[p] == nil {
+			b.setRivalTarget(p, rival)
+		}
+	}
+	for m, p := range other.mobTargets {
+		if b.mobTargets[m] == nil {
+			b.setMobTarget(m, p)
+		}
+	}
+}
+
 // IsPlayerFighting returns whether the player is fighting on this battle
 func (b *Battle) IsPlayerFighting(player *Player) bool {
-	for _, p := range b.PlayerSide {
-		if p == player {
+	return b.TeamOf(player) >= 0
+}
+
+// IsMobFighting returns whether the mob is fighting on this battle
+func (b *Battle) IsMobFighting(mob *Mob) bool {
+	for _, m := range b.MobSide {
+		if m == mob {
 			return true
 		}
 	}
 	return false
 }
 
-// IsMobFighting returns whether the mob is fighting on this battle
-func (b *Battle) IsMobFighting(mob *Mob) bool {
+// IsInRooms returns whether any player or mob of the battle is in any of the rooms
+func (b *Battle) IsInRooms(rooms map[*Room]bool) bool {
+	for _, p := range b.Players() {
+		if rooms[p.CurrentRoom] {
+			return true
+		}
+	}
 	for _, m := range b.MobSide {
-		if m == mob {
+		if rooms[m.CurrentRoom] {
 			return true
 		}
 	}
@@ -235,7 +604,57 @@ func (b *Battle) IsMobFighting(mob *Mob) bool {
 
 // NotifyAll sends a message to all the players on the battle
 func (b *Battle) NotifyAll(message string) {
-	for _, p := range b.PlayerSide {
+	for _, p := range b.Players() {
 		p.Notify(message)
 	}
 }
+
+// shareGold splits the gold dropped by the mob between the players of the battle
+func (b *Battle) shareGold(m *Mob) {
+	gold := m.DropGold()
+	players := b.Players()
+	if gold == 0 || len(players) == 0 {
+		return
+	}
+	share := gold / len(players)
+	for i, p := range players {
+		playerShare := share
+		if i < gold%len(players) {
+			playerShare++
+		}
+		if playerShare == 0 {
+			continue
+		}
+		p.Gold += playerShare
+		p.Notify(fmt.Sprintf("You get %d gold coins from %s.", playerShare, m.DefiniteName()))
+	}
+}
+
+// shareExperience splits the experience provided by the mob between the players of the battle
+func (b *Battle) shareExperience(m *Mob) {
+	players := b.Players()
+	if m.Experience <= 0 || len(players) == 0 {
+		return
+	}
+	share := max(1, m.Experience/len(players))
+	for _, p := range players {
+		p.Notify(fmt.Sprintf("You get %d experience points from %s.", share, m.DefiniteName()))
+		p.GainExperience(share)
+	}
+}
+
+// joinTeam adds the player to a team of a battle between players, along with the assistants of the player
+// who are allowed to fight the rival
+func (b *Battle) joinTeam(p *Player, team int, rival *Player, assistants []*Player) {
+	if b.IsPlayerFighting(p) {
+		return
+	}
+	b.AddPlayerToTeam(p, team)
+	for _, member := range assistants {
+		if member.pvpBlocker(rival) != "" {
+			continue
+		}
+		b.AddPlayerToTeam(member, team)
+		member.Notify(fmt.Sprintf("You assist %s!", p.Name))
+	}
+}
@@ -0,0 +1,123 @@
+package mud
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlayerTarget(t *testing.T) {
+	alice := &Player{Name: "alice", CurrentHP: 10}
+	bunny := &Mob{Appearance: Appearance{Name: "bunny"}, CurrentHP: 5}
+	dog := &Mob{Appearance: Appearance{Name: "dog"}, CurrentHP: 5}
+	b := &Battle{Teams: []Team{{alice}}, MobSide: []*Mob{bunny, dog}}
+
+	assert.Equal(t, bunny, b.PlayerTarget(alice), "with no target, attacks the first mob")
+	b.setPlayerTarget(alice, dog)
+	assert.Equal(t, dog, b.PlayerTarget(alice), "keeps the chosen target")
+	dog.CurrentHP = 0
+	assert.Equal(t, bunny, b.PlayerTarget(alice), "turns to the next mob when the target dies")
+	bunny.CurrentHP = 0
+	assert.Nil(t, b.PlayerTarget(alice), "nobody left to attack")
+}
+
+func TestMobTarget(t *testing.T) {
+	helmet := &Item{Equipment: &Equipment{Slot: Head, StatsModifiers: Stats{Constitution: 5}}}
+	newBattle := func() (*Battle, *Player, *Player, *Player) {
+		tank := &Player{Name: "tank", CurrentHP: 30, Equip: PlayerEquipment{Head: helmet}}
+		healthy := &Player{Name: "healthy", CurrentHP: 20}
+		weak := &Player{Name: "weak", CurrentHP: 2}
+		return &Battle{Teams: []Team{{healthy, weak, tank}}}, tank, healthy, weak
+	}
+
+	t.Run("attacks the tank", func(t *testing.T) {
+		b, tank, _, _ := newBattle()
+		assert.Equal(t, tank, b.MobTarget(&Mob{}))
+	})
+
+	t.Run("ties on defense go to the most health points", func(t *testing.T) {
+		b, tank, healthy, _ := newBattle()
+		tank.CurrentHP = 0
+		assert.Equal(t, healthy, b.MobTarget(&Mob{}))
+	})
+
+	t.Run("keeps the player it was fighting", func(t *testing.T) {
+		b, _, _, weak := newBattle()
+		m := &Mob{}
+		b.setMobTarget(m, weak)
+		assert.Equal(t, weak, b.MobTarget(m))
+	})
+
+	t.Run("turns to the tank when the target dies", func(t *testing.T) {
+		b, tank, _, weak := newBattle()
+		m := &Mob{}
+		b.setMobTarget(m, weak)
+		weak.CurrentHP = 0
+		assert.Equal(t, tank, b.MobTarget(m))
+	})
+
+	t.Run("bullies attack the weakest", func(t *testing.T) {
+		b, tank, _, weak := newBattle()
+		m := &Mob{Flags: []string{MobFlagBully}}
+		b.setMobTarget(m, tank)
+		assert.Equal(t, weak, b.MobTarget(m))
+		weak.CurrentHP = 0
+		assert.Equal(t, "healthy", b.MobTarget(m).Name)
+	})
+
+	t.Run("nobody left to attack", func(t *testing.T) {
+		assert.Nil(t, (&Battle{}).MobTarget(&Mob{}))
+	})
+}
+
+func TestRound(t *testing.T) {
+	t.Run("mobs killed before their turn do not attack", func(t *testing.T) {
+		alice := &Player{Name: "alice", CurrentHP: 10, Stats: Stats{Dexterity: 5}}
+		bunny := &Mob{Appearance: Appearance{Name: "bunny"}, CurrentHP: 1, Stats: Stats{Strength: 1, Dexterity: 1}}
+		b := &Battle{Teams: []Team{{alice}}, MobSide: []*Mob{bunny}}
+
+		// Initiative tie breakers, then the hit, critical and damage rolls of alice. The bunny never rolls.
+		dice := &scriptedDice{rolls: []int{0, 0, 0, 99, DamageVariance}}
+		notifications := b.round(dice)
+		assert.True(t, bunny.CurrentHP <= 0)
+		assert.Equal(t, 10, alice.CurrentHP)
+		assert.Equal(t, "alice killed the bunny!", notifications[len(notifications)-1])
+	})
+
+	t.Run("players killed mid-round neither attack nor get attacked", func(t *testing.T) {
+		alice := &Player{Name: "alice", CurrentHP: 1, Stats: Stats{Dexterity: 1}, Race: testHuman, Class: testWarrior}
+		bob := &Player{Name: "bob", CurrentHP: 10, Stats: Stats{Dexterity: 1}, Race: testHuman, Class: testWarrior}
+		wolf := &Mob{Appearance: Appearance{Name: "wolf"}, CurrentHP: 50, Stats: Stats{Strength: 3, Dexterity: 9}, Flags: []string{MobFlagBully}}
+		b := &Battle{Teams: []Team{{alice, bob}}, MobSide: []*Mob{wolf}}
+
+		// Initiative tie breakers; the wolf kills alice; bob attacks and misses
+		dice := &scriptedDice{rolls: []int{0, 50, 0, 0, 99, DamageVariance, 99}}
+		notifications := b.round(dice)
+		assert.True(t, alice.CurrentHP <= 0)
+		assert.Equal(t, 10, bob.CurrentHP)
+		assert.Equal(t, []string{
+			"The wolf inflicted 2 damage to alice.",
+			"The wolf killed alice!",
+			"bob missed the wolf.",
+		}, notifications)
+	})
+}
+
+func TestFreeAssistants(t *testing.T) {
+	room := &Room{}
+	alice := &Player{Name: "alice", CurrentRoom: room}
+	busy := &Player{Name: "busy", CurrentRoom: room}
+	free := &Player{Name: "free", CurrentRoom: room}
+	joined := &Player{Name: "joined", CurrentRoom: room}
+	alice.group = &Group{Leader: alice, Members: []*Player{alice, busy, free, joined}}
+	for _, p := range alice.group.Members {
+		p.group = alice.group
+	}
+
+	elsewhere := &Battle{Teams: []Team{{busy}}}
+	current := &Battle{Teams: []Team{{joined}}}
+	w := &World{battles: []*Battle{elsewhere, current}}
+
+	assert.Equal(t, []*Player{free, joined}, w.freeAssistants(alice, current), "members fighting elsewhere are left out")
+	assert.Equal(t, []*Player{free}, w.freeAssistants(alice))
+}
@@ -1,13 +1,57 @@
 package mud
 
-// PlayerClass denotes the class of a player (Warrior, Mage, Rogue...)
-type PlayerClass int
-
-const (
-	// Warrior excells in Strength and Constitution and related skills
-	Warrior PlayerClass = iota
-	// Mage excells in Intelligence and Wisdom and related skills
-	Mage
-	// Rogue excells in Dexterity and Luck and related skills
-	Rogue
+import (
+	"fmt"
+	"path/filepath"
 )
+
+// DefaultClass is the ID of the class of the new characters
+const DefaultClass = "warrior"
+
+// legacyClasses are the IDs of the classes saved by number, in the order they used to have
+var legacyClasses = []string{"warrior", "mage", "rogue"}
+
+// Class denotes the class of a player (Warrior, Mage, Rogue...), as defined on assets/classes.json
+type Class struct {
+	Traits
+}
+
+// ClassID is the ID of a class as saved with the players
+type ClassID string
+
+// UnmarshalJSON unmarshals the class ID, translating the classes saved by number
+func (id *ClassID) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalLegacyID(b, legacyClasses)
+	*id = ClassID(s)
+	return err
+}
+
+// LoadClasses loads all the classes defined on assets/classes.json
+func (w *World) LoadClasses(bundlePath string) error {
+	var classes []*Class
+	if err := decodeAssetFile(filepath.Join(bundlePath, "assets", "classes.json"), &classes); err != nil {
+		return err
+	}
+
+	classesDB := make(map[string]*Class)
+	for _, c := range classes {
+		if _, ok := classesDB[c.ID]; ok {
+			return fmt.Errorf("class ID %s duplicated", c.ID)
+		}
+		classesDB[c.ID] = c
+	}
+	if classesDB[DefaultClass] == nil {
+		return fmt.Errorf("default class %s not defined", DefaultClass)
+	}
+
+	w.classesDB = classesDB
+	return nil
+}
+
+// class returns the class with the ID, or the default class if there is none
+func (w *World) class(id ClassID) *Class {
+	if c, ok := w.classesDB[string(id)]; ok {
+		return c
+	}
+	return w.classesDB[DefaultClass]
+}
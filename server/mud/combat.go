@@ -0,0 +1,183 @@
+package mud
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+const (
+	// BaseHitChance is the percentage of attacks that hit when attacker and defender have the same Dexterity
+	BaseHitChance = 75
+	// HitChancePerDexterity is how much the hit chance changes for each point of Dexterity of difference
+	HitChancePerDexterity = 5
+	// MinHitChance is the lowest hit chance, so anyone can land a lucky blow
+	MinHitChance = 5
+	// MaxHitChance is the highest hit chance, so anyone can dodge a blow
+	MaxHitChance = 95
+	// BaseCritChance is the percentage of hits that are critical with no Luck
+	BaseCritChance = 5
+	// CritChancePerLuck is how much the critical chance grows for each point of Luck
+	CritChancePerLuck = 2
+	// MaxCritChance is the highest critical chance
+	MaxCritChance = 50
+	// CritMultiplier is how many times the damage a critical hit inflicts
+	CritMultiplier = 2
+	// DamageVariance is the percentage the damage may vary above or below its expected value
+	DamageVariance = 20
+	// UnarmedAttack is the attack of the bare hands, before adding Strength
+	UnarmedAttack = 1
+)
+
+// Dice rolls the random numbers of the combat. *rand.Rand satisfies it, so the formulas can be tested with a seeded one
+type Dice interface {
+	// Intn returns a random number in [0, n)
+	Intn(n int) int
+}
+
+// globalDice rolls with the global source of math/rand, which is safe to use from several battles at once
+type globalDice struct{}
+
+func (globalDice) Intn(n int) int {
+	return rand.Intn(n)
+}
+
+// Combatant is a player or a mob, as seen by the combat formulas
+type Combatant interface {
+	// GetCombatStat returns the stat as used in combat
+	GetCombatStat(s Stat) int
+	// GetCurrentDefense returns how much damage is absorbed from each hit
+	GetCurrentDefense() int
+}
+
+// Attack is a single blow of a combatant on a round
+type Attack struct {
+	// Power is the attack before the defense of the target is applied
+	Power int
+	// Weapon is the item used, or nil for bare hands and natural attacks
+	Weapon *Item
+	// Possessive is the possessive determiner used before the name of the weapon instead of its article, like her. Empty keeps the article
+	Possessive string
+}
+
+// weaponName returns how the weapon is named on the battle messages. Example: her rusty sword
+func (a Attack) weaponName() string {
+	if a.Possessive == "" {
+		return a.Weapon.Name
+	}
+	for _, article := range []string{"a ", "an ", "the ", "some "} {
+		if strings.HasPrefix(a.Weapon.Name, article) {
+			return a.Possessive + " " + strings.TrimPrefix(a.Weapon.Name, article)
+		}
+	}
+	return a.Weapon.Name
+}
+
+// AttackResult is the outcome of an attack
+type AttackResult struct {
+	// Hit denotes whether the attack landed or was evaded
+	Hit bool
+	// Critical denotes whether the hit was critical
+	Critical bool
+	// Damage is how many HP the target loses
+	Damage int
+}
+
+// HitChance returns the percentage of attacks that land, given the Dexterity of the attacker and the defender
+func HitChance(attackerDexterity, defenderDexterity int) int {
+	chance := BaseHitChance + (attackerDexterity-defenderDexterity)*HitChancePerDexterity
+	return max(MinHitChance, min(chance, MaxHitChance))
+}
+
+// CritChance returns the percentage of hits that are critical, given the Luck of the attacker
+func CritChance(luck int) int {
+	chance := BaseCritChance + luck*CritChancePerLuck
+	return max(0, min(chance, MaxCritChance))
+}
+
+// RollDamage returns the damage inflicted by an attack that hit, which is always at least 1
+func RollDamage(dice Dice, power, defense int, critical bool) int {
+	damage := max(power-defense, 1)
+	damage = damage * (100 - DamageVariance + dice.Intn(2*DamageVariance+1)) / 100
+	if critical {
+		damage *= CritMultiplier
+	}
+	return max(damage, 1)
+}
+
+// RollAttack rolls whether the attack hits the defender, whether it is critical and how much damage it inflicts
+func RollAttack(dice Dice, attacker, defender Combatant, attack Attack) AttackResult {
+	chance := HitChance(attacker.GetCombatStat(Dexterity), defender.GetCombatStat(Dexterity))
+	if dice.Intn(100) >= chance {
+		return AttackResult{}
+	}
+
+	critical := dice.Intn(100) < CritChance(attacker.GetCombatStat(Luck))
+	return AttackResult{
+		Hit:      true,
+		Critical: critical,
+		Damage:   RollDamage(dice, attack.Power, defender.GetCurrentDefense(), critical),
+	}
+}
+
+// InitiativeOrder returns the combatants in the order they act on a round: the highest Dexterity goes first,
+// and ties are broken at random
+func InitiativeOrder(dice Dice, combatants []Combatant) []Combatant {
+	order := make([]Combatant, len(combatants))
+	tieBreakers := make(map[Combatant]int, len(combatants))
+	for i, c := range combatants {
+		order[i] = c
+		tieBreakers[c] = dice.Intn(100)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		first, second := order[i].GetCombatStat(Dexterity), order[j].GetCombatStat(Dexterity)
+		if first != second {
+			return first > second
+		}
+		return tieBreakers[order[i]] > tieBreakers[order[j]]
+	})
+	return order
+}
+
+// Attacks returns the blows of the player on each round: one for each hand with a weapon, or bare hands if there is none
+func (p *Player) Attacks() []Attack {
+	attacks := []Attack{}
+	if right := p.GetRightAttack(); right > 0 {
+		attacks = append(attacks, Attack{Power: right, Weapon: p.Equip[RightHand]})
+	}
+	if left := p.GetLeftAttack(); left > 0 {
+		attacks = append(attacks, Attack{Power: left, Weapon: p.Equip[LeftHand]})
+	}
+	if len(attacks) == 0 {
+		power := max(0, UnarmedAttack+p.GetCurrentStat(Strength)+p.Equip.GetAttackModifiers()+p.effects().GetAttackModifiers())
+		attacks = append(attacks, Attack{Power: power})
+	}
+	return attacks
+}
+
+// Attacks returns the blows of the mob on each round, with the weapon it wields if any
+func (m *Mob) Attacks() []Attack {
+	return []Attack{{Power: m.GetAttack(), Weapon: m.Equip[RightHand], Possessive: m.Gender.Possessive()}}
+}
+
+// GetCombatStat returns the stat of the mob as used in combat
+func (m *Mob) GetCombatStat(s Stat) int {
+	return m.GetCurrentStat(s)
+}
+
+// describeAttack returns the battle message of an attack
+func describeAttack(attacker, target string, attack Attack, result AttackResult) string {
+	with := ""
+	if attack.Weapon != nil {
+		with = " with " + attack.weaponName()
+	}
+	switch {
+	case !result.Hit:
+		return fmt.Sprintf("%s missed %s%s.", attacker, target, with)
+	case result.Critical:
+		return fmt.Sprintf("%s landed a critical hit on %s%s, inflicting %d damage!", attacker, target, with, result.Damage)
+	default:
+		return fmt.Sprintf("%s inflicted %d damage to %s%s.", attacker, result.Damage, target, with)
+	}
+}
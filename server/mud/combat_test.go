@@ -0,0 +1,192 @@
+package mud
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubCombatant is a combatant with fixed stats, so the formulas can be tested on their own
+type stubCombatant struct {
+	stats   Stats
+	defense int
+}
+
+func (c *stubCombatant) GetCombatStat(s Stat) int {
+	return c.stats[s]
+}
+
+func (c *stubCombatant) GetCurrentDefense() int {
+	return c.defense
+}
+
+// scriptedDice returns the given rolls in order, to check exact outcomes
+type scriptedDice struct {
+	rolls []int
+}
+
+func (d *scriptedDice) Intn(n int) int {
+	roll := d.rolls[0]
+	d.rolls = d.rolls[1:]
+	return roll
+}
+
+func TestHitChance(t *testing.T) {
+	tests := []struct {
+		name     string
+		attacker int
+		defender int
+		expected int
+	}{
+		{"same dexterity", 3, 3, BaseHitChance},
+		{"more dexterous attacker", 5, 3, BaseHitChance + 2*HitChancePerDexterity},
+		{"more dexterous defender", 3, 5, BaseHitChance - 2*HitChancePerDexterity},
+		{"capped", 20, 0, MaxHitChance},
+		{"floored", 0, 20, MinHitChance},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, HitChance(tt.attacker, tt.defender))
+		})
+	}
+}
+
+func TestCritChance(t *testing.T) {
+	tests := []struct {
+		name     string
+		luck     int
+		expected int
+	}{
+		{"no luck", 0, BaseCritChance},
+		{"some luck", 5, BaseCritChance + 5*CritChancePerLuck},
+		{"capped", 100, MaxCritChance},
+		{"bad luck never goes negative", -10, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CritChance(tt.luck))
+		})
+	}
+}
+
+func TestRollDamage(t *testing.T) {
+	dice := rand.New(rand.NewSource(1))
+
+	t.Run("varies around attack minus defense", func(t *testing.T) {
+		seen := map[int]bool{}
+		for i := 0; i < 1000; i++ {
+			damage := RollDamage(dice, 20, 10, false)
+			assert.True(t, damage >= 8 && damage <= 12, "damage %d out of range", damage)
+			seen[damage] = true
+		}
+		assert.Len(t, seen, 5, "all the values of the range should appear")
+	})
+
+	t.Run("critical multiplies the damage", func(t *testing.T) {
+		for i := 0; i < 1000; i++ {
+			damage := RollDamage(dice, 20, 10, true)
+			assert.True(t, damage >= 8*CritMultiplier && damage <= 12*CritMultiplier, "damage %d out of range", damage)
+		}
+	})
+
+	t.Run("always at least 1", func(t *testing.T) {
+		for i := 0; i < 1000; i++ {
+			assert.Equal(t, 1, RollDamage(dice, 0, 10, false))
+		}
+	})
+}
+
+func TestRollAttack(t *testing.T) {
+	attacker := &stubCombatant{stats: Stats{Dexterity: 4, Luck: 5}}
+	defender := &stubCombatant{stats: Stats{Dexterity: 2}, defense: 2}
+	attack := Attack{Power: 12}
+	hitChance := HitChance(4, 2)
+	critChance := CritChance(5)
+
+	t.Run("miss", func(t *testing.T) {
+		dice := &scriptedDice{rolls: []int{hitChance}}
+		assert.Equal(t, AttackResult{}, RollAttack(dice, attacker, defender, attack))
+	})
+
+	t.Run("hit", func(t *testing.T) {
+		dice := &scriptedDice{rolls: []int{hitChance - 1, critChance, DamageVariance}}
+		assert.Equal(t, AttackResult{Hit: true, Damage: 10}, RollAttack(dice, attacker, defender, attack))
+	})
+
+	t.Run("critical hit", func(t *testing.T) {
+		dice := &scriptedDice{rolls: []int{0, critChance - 1, DamageVariance}}
+		assert.Equal(t, AttackResult{Hit: true, Critical: true, Damage: 10 * CritMultiplier}, RollAttack(dice, attacker, defender, attack))
+	})
+
+	t.Run("rates follow the chances with a seeded RNG", func(t *testing.T) {
+		dice := rand.New(rand.NewSource(42))
+		rolls, hits, crits := 10000, 0, 0
+		for i := 0; i < rolls; i++ {
+			result := RollAttack(dice, attacker, defender, attack)
+			if result.Hit {
+				hits++
+			}
+			if result.Critical {
+				crits++
+			}
+		}
+		assert.InDelta(t, hitChance, hits*100/rolls, 2)
+		assert.InDelta(t, critChance, crits*100/hits, 2)
+	})
+
+	t.Run("same seed gives the same fight", func(t *testing.T) {
+		first := rand.New(rand.NewSource(7))
+		second := rand.New(rand.NewSource(7))
+		for i := 0; i < 100; i++ {
+			assert.Equal(t, RollAttack(first, attacker, defender, attack), RollAttack(second, attacker, defender, attack))
+		}
+	})
+}
+
+func TestInitiativeOrder(t *testing.T) {
+	slow := &stubCombatant{stats: Stats{Dexterity: 1}}
+	fast := &stubCombatant{stats: Stats{Dexterity: 8}}
+	average := &stubCombatant{stats: Stats{Dexterity: 4}}
+	alsoAverage := &stubCombatant{stats: Stats{Dexterity: 4}}
+
+	t.Run("highest dexterity first", func(t *testing.T) {
+		dice := &scriptedDice{rolls: []int{0, 0, 0}}
+		order := InitiativeOrder(dice, []Combatant{slow, fast, average})
+		assert.Equal(t, []Combatant{fast, average, slow}, order)
+	})
+
+	t.Run("ties are broken by the dice", func(t *testing.T) {
+		dice := &scriptedDice{rolls: []int{10, 90}}
+		assert.Equal(t, []Combatant{alsoAverage, average}, InitiativeOrder(dice, []Combatant{average, alsoAverage}))
+		dice = &scriptedDice{rolls: []int{90, 10}}
+		assert.Equal(t, []Combatant{average, alsoAverage}, InitiativeOrder(dice, []Combatant{average, alsoAverage}))
+	})
+
+	t.Run("does not modify the original list", func(t *testing.T) {
+		combatants := []Combatant{slow, fast}
+		InitiativeOrder(&scriptedDice{rolls: []int{0, 0}}, combatants)
+		assert.Equal(t, []Combatant{slow, fast}, combatants)
+	})
+}
+
+func TestDescribeAttack(t *testing.T) {
+	sword := &Item{Name: "a rusty sword"}
+	tests := []struct {
+		name     string
+		attack   Attack
+		result   AttackResult
+		expected string
+	}{
+		{"miss", Attack{Weapon: sword}, AttackResult{}, "alice missed the bunny with a rusty sword."},
+		{"hit", Attack{Weapon: sword}, AttackResult{Hit: true, Damage: 4}, "alice inflicted 4 damage to the bunny with a rusty sword."},
+		{"critical", Attack{}, AttackResult{Hit: true, Critical: true, Damage: 8}, "alice landed a critical hit on the bunny, inflicting 8 damage!"},
+		{"possessive", Attack{Weapon: sword, Possessive: Female.Possessive()}, AttackResult{}, "alice missed the bunny with her rusty sword."},
+		{"possessive of a named weapon", Attack{Weapon: &Item{Name: "Excalibur"}, Possessive: Male.Possessive()}, AttackResult{}, "alice missed the bunny with Excalibur."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, describeAttack("alice", "the bunny", tt.attack, tt.result))
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package mud
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ConsumableKind denotes what kind of consumable an item is, which sets the command used to consume it
+type ConsumableKind int
+
+const (
+	// Potion is quaffed. It is the default kind
+	Potion ConsumableKind = iota
+	// Scroll is read
+	Scroll
+	// Food is eaten
+	Food
+	// Drink is drunk
+	Drink
+	// Wand is used, spending one of its charges
+	Wand
+	// Staff is used, spending one of its charges
+	Staff
+)
+
+// consumableKindNames maps each kind of consumable to the name used on the JSON files
+var consumableKindNames = map[ConsumableKind]string{
+	Potion: "potion",
+	Scroll: "scroll",
+	Food:   "food",
+	Drink:  "drink",
+	Wand:   "wand",
+	Staff:  "staff",
+}
+
+// consumableVerbs maps each kind of consumable to the command that consumes it, and how others see it
+var consumableVerbs = map[ConsumableKind][2]string{
+	Potion: {"quaff", "quaffs"},
+	Scroll: {"read", "reads"},
+	Food:   {"eat", "eats"},
+	Drink:  {"drink", "drinks"},
+	Wand:   {"use", "uses"},
+	Staff:  {"use", "uses"},
+}
+
+// MarshalText marshals the kind of consumable into its name
+func (k ConsumableKind) MarshalText() ([]byte, error) {
+	name, ok := consumableKindNames[k]
+	if !ok {
+		return nil, fmt.Errorf("unknown consumable kind %d", k)
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText unmarshals the kind of consumable from its name
+func (k *ConsumableKind) UnmarshalText(b []byte) error {
+	for key, v := range consumableKindNames {
+		if v == string(b) {
+			*k = key
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown consumable kind %s", string(b))
+}
+
+// Consumable represents the properties of an item that can be consumed, like potions, scrolls, food or wands
+type Consumable struct {
+	// Kind denotes whether it is quaffed, read, eaten, drunk or used
+	Kind ConsumableKind `json:"kind"`
+	// Heal is how many health points it restores
+	Heal int `json:"heal"`
+	// Effects are the effects granted for the duration, like seeing hidden things
+	Effects EffectList `json:"effects"`
+	// Duration is how many minutes the effects last
+	Duration int `json:"duration"`
+	// Recall teleports the player to the recall room of the area, or to the default room outside areas
+	Recall bool `json:"recall"`
+	// Charges is how many times wands and staves can be used before crumbling. Every other kind is used up at once
+	Charges int `json:"charges"`
+}
+
+// hasCharges returns whether the consumable is used up charge by charge instead of at once
+func (c *Consumable) hasCharges() bool {
+	return c.Kind == Wand || c.Kind == Staff
+}
+
+// Consume consumes the item with the command given by verb: quaff, read, eat, drink or use. While fighting,
+// the item is consumed on the next turn of the battle instead of attacking
+func (p *Player) Consume(verb, keyword string) {
+	if p.IsSleeping() {
+		p.Notify("You cannot do that while sleeping.")
+		return
+	}
+	if p.tooGhostly() {
+		return
+	}
+	if keyword == "" {
+		p.Notify(fmt.Sprintf("What do you want to %s?", verb))
+		return
+	}
+
+	item := p.Inventory.Find(keyword)
+	if item == nil {
+		p.Notify(fmt.Sprintf("You do not have any %s.", keyword))
+		return
+	}
+	if item.Consumable == nil || consumableVerbs[item.Consumable.Kind][0] != verb {
+		p.Notify(fmt.Sprintf("You cannot %s %s.", verb, item.Name))
+		return
+	}
+
+	if p.IsFighting() {
+		p.queued = item
+		p.Notify(fmt.Sprintf("You will %s %s on your next turn.", verb, item.Name))
+		return
+	}
+	p.CurrentRoom.Act(p, p.consume(item))
+	p.applyConsumable(item.Consumable)
+}
+
+// consumeQueued consumes the item queued during the battle, if the player still carries it.
+// Returns the message for the rest of the battle, or an empty string if nothing was consumed
+func (p *Player) consumeQueued() string {
+	item := p.queued
+	p.queued = nil
+	if item == nil || !p.carries(item) {
+		return ""
+	}
+	act := p.consume(item)
+	p.applyConsumable(item.Consumable)
+	return act
+}
+
+// carries returns whether the item is on the inventory of the player
+func (p *Player) carries(item *Item) bool {
+	for _, v := range p.Inventory {
+		if v == item {
+			return true
+		}
+	}
+	return false
+}
+
+// consume spends the item, removing it from the inventory once used up. Returns how others see it, like alice quaffs a red potion
+func (p *Player) consume(item *Item) string {
+	c := item.Consumable
+	verbs := consumableVerbs[c.Kind]
+	p.Notify(fmt.Sprintf("You %s %s.", verbs[0], item.Name))
+	act := fmt.Sprintf("%s %s %s.", p.Name, verbs[1], item.Name)
+
+	if c.hasCharges() {
+		c.Charges--
+	}
+	if !c.hasCharges() || c.Charges <= 0 {
+		p.Inventory = p.Inventory.Remove(item)
+	}
+	if c.hasCharges() && c.Charges <= 0 {
+		p.Notify(fmt.Sprintf("%s crumbles to dust.", capitalize(item.Name)))
+	}
+	return act
+}
+
+// applyConsumable heals the player, grants the effects for their duration and recalls the player, as the consumable says
+func (p *Player) applyConsumable(c *Consumable) {
+	if c.Heal > 0 {
+		p.CurrentHP = min(p.MaxHP, p.CurrentHP+c.Heal)
+		p.Notify("You feel better.")
+	}
+	for _, e := range c.Effects {
+		effect := *e
+		effect.ExpiresAt = time.Now().Add(time.Duration(c.Duration) * time.Minute)
+		p.Effects = append(p.Effects, &effect)
+		if effect.Name != "" {
+			p.Notify(fmt.Sprintf("You are under the effect of %s.", effect.Name))
+		}
+	}
+	if room := p.recallRoom(); c.Recall && room != nil {
+		p.Teleport(room)
+	}
+}
+
+// examine returns the properties of the consumable, shown when examining the item
+func (c *Consumable) examine() []string {
+	lines := []string{"Consumable: " + consumableKindNames[c.Kind]}
+	if c.Heal > 0 {
+		lines = append(lines, fmt.Sprintf("Heals: %d HP", c.Heal))
+	}
+	if effects := c.Effects.Names(); len(effects) > 0 {
+		lines = append(lines, fmt.Sprintf("Magic: %s for %d minutes", strings.Join(effects, ", "), c.Duration))
+	}
+	if c.Recall {
+		lines = append(lines, "Recalls you to safety")
+	}
+	if c.hasCharges() {
+		lines = append(lines, fmt.Sprintf("Charges: %d", c.Charges))
+	}
+	return lines
+}
@@ -0,0 +1,152 @@
+package mud
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsume(t *testing.T) {
+	potion := &Item{ID: "potion", Name: "a red potion", Consumable: &Consumable{Kind: Potion, Heal: 30}}
+	wand := &Item{ID: "wand", Name: "a wand of sight", Consumable: &Consumable{Kind: Wand, Effects: EffectList{{Name: "magical sight", SeeHidden: true}}, Duration: 10, Charges: 2}}
+	sword := &Item{ID: "sword", Name: "a sword", Equipment: &Equipment{Slot: RightHand}}
+
+	tests := []struct {
+		name              string
+		verb              string
+		item              *Item
+		expectedHP        int
+		expectedSeeHidden bool
+		expectedCharges   int
+		expectedUsedUp    bool
+	}{
+		{"quaff heals up to the maximum", "quaff", potion, 50, false, 0, true},
+		{"wrong verb", "eat", potion, 40, false, 0, false},
+		{"not consumable", "quaff", sword, 40, false, 0, false},
+		{"wand spends a charge", "use", wand, 40, true, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := tt.item.Spawn()
+			p := &Player{Name: "alice", MaxHP: 50, CurrentHP: 40, Inventory: ItemList{item}, CurrentRoom: &Room{}}
+			p.Notify = func(string) {}
+
+			p.Consume(tt.verb, tt.item.ID)
+			assert.Equal(t, tt.expectedHP, p.CurrentHP)
+			assert.Equal(t, tt.expectedSeeHidden, p.CanSeeHidden())
+			assert.Equal(t, tt.expectedUsedUp, !p.carries(item))
+			if item.Consumable != nil && item.Consumable.hasCharges() {
+				assert.Equal(t, tt.expectedCharges, item.Consumable.Charges)
+				assert.Equal(t, 2, tt.item.Consumable.Charges, "the template keeps its charges")
+			}
+		})
+	}
+
+	t.Run("the last charge crumbles the wand", func(t *testing.T) {
+		item := wand.Spawn()
+		item.Consumable.Charges = 1
+		p := &Player{Name: "alice", Inventory: ItemList{item}, CurrentRoom: &Room{}}
+		p.Notify = func(string) {}
+
+		p.Consume("use", "wand")
+		assert.False(t, p.carries(item))
+		require.Len(t, p.Effects, 1)
+		assert.WithinDuration(t, time.Now().Add(10*time.Minute), p.Effects[0].ExpiresAt, time.Second)
+	})
+}
+
+func TestConsumeInBattle(t *testing.T) {
+	t.Run("queued items take the turn of the player", func(t *testing.T) {
+		potion := &Item{ID: "potion", Name: "a red potion", Consumable: &Consumable{Kind: Potion, Heal: 30}}
+		alice := &Player{Name: "alice", MaxHP: 50, CurrentHP: 5, Position: Fighting, Stats: Stats{Dexterity: 5}, Inventory: ItemList{potion}, CurrentRoom: &Room{}}
+		alice.Notify = func(string) {}
+		bunny := &Mob{Appearance: Appearance{Name: "bunny"}, CurrentHP: 10, Stats: Stats{Dexterity: 1}}
+		b := &Battle{Teams: []Team{{alice}}, MobSide: []*Mob{bunny}}
+
+		alice.Consume("quaff", "potion")
+		assert.Equal(t, 5, alice.CurrentHP, "nothing happens until the next round")
+
+		// Initiative tie breakers, then the bunny misses
+		dice := &scriptedDice{rolls: []int{0, 0, 99}}
+		notifications := b.round(dice)
+		assert.Equal(t, 35, alice.CurrentHP)
+		assert.Equal(t, 10, bunny.CurrentHP)
+		assert.Equal(t, "alice quaffs a red potion.", notifications[0])
+		assert.Nil(t, alice.queued)
+	})
+
+	t.Run("items queued for battles that ended are not consumed", func(t *testing.T) {
+		tests := []struct {
+			name string
+			end  func(b *Battle, p *Player)
+		}{
+			{"battle ended", func(b *Battle, p *Player) { b.end() }},
+			{"player removed", func(b *Battle, p *Player) { b.RemovePlayer(p) }},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				potion := &Item{ID: "potion", Name: "a red potion", Consumable: &Consumable{Kind: Potion, Heal: 30}}
+				alice := &Player{Name: "alice", MaxHP: 50, CurrentHP: 5, Position: Fighting, Inventory: ItemList{potion}, CurrentRoom: &Room{}}
+				alice.Notify = func(string) {}
+				b := &Battle{Teams: []Team{{alice}}, MobSide: []*Mob{{Appearance: Appearance{Name: "bunny"}, CurrentHP: 10}}, battleEnded: make(chan struct{})}
+
+				alice.Consume("quaff", "potion")
+				tt.end(b, alice)
+
+				alice.Position = Fighting
+				bunny := &Mob{Appearance: Appearance{Name: "bunny"}, CurrentHP: 10}
+				next := &Battle{Teams: []Team{{alice}}, MobSide: []*Mob{bunny}}
+				// Initiative tie breakers, alice hits without a critical, then the bunny misses
+				next.round(&scriptedDice{rolls: []int{0, 0, 0, 99, 0, 99}})
+				assert.Equal(t, 5, alice.CurrentHP)
+				assert.True(t, alice.carries(potion))
+				assert.Less(t, bunny.CurrentHP, 10, "alice attacks instead")
+			})
+		}
+	})
+
+	t.Run("recalling leaves the battle", func(t *testing.T) {
+		scroll := &Item{ID: "scroll", Name: "a scroll of recall", Consumable: &Consumable{Kind: Scroll, Recall: true}}
+		field := &Room{ID: "field", Players: map[string]*Player{}}
+		temple := &Room{ID: "temple", Players: map[string]*Player{}}
+		alice := &Player{UserID: "alice", Name: "alice", CurrentHP: 10, Position: Fighting, Inventory: ItemList{scroll}, CurrentRoom: field, DefaultRoom: temple}
+		alice.Notify = func(string) {}
+		field.Players[alice.UserID] = alice
+		b := &Battle{Teams: []Team{{alice}}}
+
+		alice.Consume("read", "scroll")
+		notifications := b.round(&scriptedDice{rolls: []int{0}})
+		assert.Equal(t, []string{"alice reads a scroll of recall."}, notifications)
+		assert.Equal(t, temple, alice.CurrentRoom)
+		assert.Empty(t, b.Players())
+		assert.Equal(t, Standing, alice.Position)
+	})
+}
+
+func TestRecall(t *testing.T) {
+	temple := &Room{ID: "temple", Players: map[string]*Player{}}
+	lobby := &Room{ID: "arena_lobby", Players: map[string]*Player{}}
+	tests := []struct {
+		name     string
+		area     *Area
+		expected *Room
+	}{
+		{"recall room of the area", &Area{RecallRoom: lobby}, lobby},
+		{"outside areas", nil, temple},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scroll := &Item{ID: "scroll", Name: "a scroll of recall", Consumable: &Consumable{Kind: Scroll, Recall: true}}
+			pit := &Room{ID: "arena_pit", Players: map[string]*Player{}, area: tt.area}
+			alice := &Player{UserID: "alice", Name: "alice", Inventory: ItemList{scroll}, CurrentRoom: pit, DefaultRoom: temple}
+			alice.Notify = func(string) {}
+			pit.Players[alice.UserID] = alice
+
+			alice.Consume("read", "scroll")
+			assert.Equal(t, tt.expected, alice.CurrentRoom)
+			delete(tt.expected.Players, alice.UserID)
+		})
+	}
+}
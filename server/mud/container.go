@@ -1 +1,149 @@
 package mud
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// CorpseID is the item ID shared by all the corpses
+	CorpseID = "corpse"
+	// CorpseLifespan defines how long a corpse stays on the ground before rotting away
+	CorpseLifespan = 5 * time.Minute
+)
+
+// Container contains the properties of the items that can hold other items, like bags, chests and corpses
+type Container struct {
+	// Capacity is how many items fit inside. 0 means no limit
+	Capacity int `json:"capacity"`
+	// MaxWeight is the maximum total weight of the items inside. 0 means no limit
+	MaxWeight int `json:"max_weight"`
+	// Closeable denotes whether the container has a lid that can be opened and closed
+	Closeable bool `json:"closeable"`
+	// IsClosed denotes whether the container is closed
+	IsClosed bool `json:"closed"`
+	// IsLocked denotes whether the container is locked
+	IsLocked bool `json:"locked"`
+	// Key is the ID of the item needed to lock and unlock the container. Empty string would mean it can be unlocked without any key.
+	Key string `json:"key"`
+	// Contents are the items inside. Item files must leave it empty and fill the containers with put resets
+	Contents ItemList `json:"contents,omitempty"`
+}
+
+// spawn creates a new container using another one as template, with copies of all the items inside
+func (c *Container) spawn() *Container {
+	newContainer := *c
+	newContainer.Contents = make(ItemList, 0, len(c.Contents))
+	for _, item := range c.Contents {
+		newContainer.Contents = append(newContainer.Contents, item.Spawn())
+	}
+	return &newContainer
+}
+
+// Weight returns the total weight of the items inside
+func (c *Container) Weight() int {
+	weight := 0
+	for _, item := range c.Contents {
+		weight += item.TotalWeight()
+	}
+	return weight
+}
+
+// IsFull returns whether there is no room for one more item
+func (c *Container) IsFull() bool {
+	return c.Capacity > 0 && len(c.Contents) >= c.Capacity
+}
+
+// CanBear returns whether the container can bear the weight of the item on top of its current contents
+func (c *Container) CanBear(item *Item) bool {
+	return c.MaxWeight <= 0 || c.Weight()+item.TotalWeight() <= c.MaxWeight
+}
+
+// Holds returns whether the item is the container itself or is inside it, at any depth
+func (c *Container) Holds(item *Item) bool {
+	for _, v := range c.Contents {
+		if v == item || (v.Container != nil && v.Container.Holds(item)) {
+			return true
+		}
+	}
+	return false
+}
+
+// State returns whether the container is open, closed or locked
+func (c *Container) State() DoorState {
+	switch {
+	case c.IsLocked:
+		return DoorLocked
+	case c.IsClosed:
+		return DoorClosed
+	default:
+		return DoorOpen
+	}
+}
+
+// Show returns the list of items inside, as seen when a player looks in the container
+func (c *Container) Show(name string) string {
+	if c.IsClosed {
+		return capitalize(name) + " is closed."
+	}
+	if len(c.Contents) == 0 {
+		return capitalize(name) + " is empty."
+	}
+	return fmt.Sprintf("%s contains:\n* %s", capitalize(name), strings.Join(c.Contents.Names(), "\n* "))
+}
+
+// examine returns the properties of the container shown when a player examines it
+func (c *Container) examine() []string {
+	lines := []string{}
+	if c.Capacity > 0 {
+		lines = append(lines, fmt.Sprintf("Holds: %d/%d items", len(c.Contents), c.Capacity))
+	} else {
+		lines = append(lines, fmt.Sprintf("Holds: %d items", len(c.Contents)))
+	}
+	if c.MaxWeight > 0 {
+		lines = append(lines, fmt.Sprintf("Weight inside: %d/%d", c.Weight(), c.MaxWeight))
+	}
+	switch {
+	case c.IsLocked:
+		lines = append(lines, "It is locked.")
+	case c.IsClosed:
+		lines = append(lines, "It is closed.")
+	case c.Closeable:
+		lines = append(lines, "It is open.")
+	}
+	return lines
+}
+
+// newCorpse creates the corpse left by a mob or a player, holding the items it carried
+func newCorpse(owner string, keywords []string, items ItemList) *Item {
+	name := "the corpse of " + owner
+	return &Item{
+		ID:          CorpseID,
+		Name:        name,
+		Description: fmt.Sprintf("This is %s. It does not look like it will stand up again.", name),
+		Keywords:    append([]string{"corpse"}, keywords...),
+		NoTake:      true,
+		Container:   &Container{Contents: items},
+		decayAt:     time.Now().Add(CorpseLifespan),
+	}
+}
+
+// Decayed returns whether the item has rotted away and should be removed from the ground
+func (i *Item) Decayed(now time.Time) bool {
+	return !i.decayAt.IsZero() && now.After(i.decayAt)
+}
+
+// rotItems removes the decayed items from the ground of the room, leaving what they held on the ground
+func (r *Room) rotItems(now time.Time) {
+	for _, item := range append(ItemList{}, r.Items...) {
+		if !item.Decayed(now) {
+			continue
+		}
+		r.Items = r.Items.Remove(item)
+		if item.Container != nil {
+			r.Items = append(r.Items, item.Container.Contents...)
+		}
+		r.Announce(capitalize(item.Name) + " rots away.")
+	}
+}
@@ -1,16 +1,45 @@
 package mud
 
+import "time"
+
 // EffectList list the different effects that any player, mob or item may have
 type EffectList []*Effect
 
 // Effect denotes any kind of effect that any player, mob or item may have
 type Effect struct {
-	Attack         int
-	StatsModifiers Stats
-	SeeHidden      bool
-	SeeInvisible   bool
-	GrantInvisible bool
-	GrantHidden    bool
+	// Name is how the effect is called when it wears off, like weakness
+	Name string `json:"name"`
+	// Attack denotes how much attack the effect grants
+	Attack int `json:"attack"`
+	// StatsModifiers denotes how much the effect modifies each stat
+	StatsModifiers Stats `json:"stats_modifiers"`
+	// SeeHidden lets you see hidden things
+	SeeHidden bool `json:"see_hidden"`
+	// SeeInvisible lets you see invisible things
+	SeeInvisible bool `json:"see_invisible"`
+	// GrantInvisible renders you invisible
+	GrantInvisible bool `json:"grant_invisible"`
+	// GrantHidden renders you hidden
+	GrantHidden bool `json:"grant_hidden"`
+	// ExpiresAt is when a temporary effect wears off, like the weakness after dying. Effects of items and mobs leave it empty, as they last forever
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Expired returns whether the effect is temporary and has already worn off
+func (e *Effect) Expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && now.After(e.ExpiresAt)
+}
+
+// Expire splits the list between the effects that still last and the ones that have worn off
+func (el EffectList) Expire(now time.Time) (active, expired EffectList) {
+	for _, v := range el {
+		if v.Expired(now) {
+			expired = append(expired, v)
+			continue
+		}
+		active = append(active, v)
+	}
+	return active, expired
 }
 
 // GetAttackModifiers returns the modifiers to attack provided by all the effects on the list
@@ -70,3 +99,21 @@ func (el EffectList) GrantHidden() bool {
 	}
 	return false
 }
+
+// Names returns the names of the magical abilities granted by the effects on the list
+func (el EffectList) Names() []string {
+	names := []string{}
+	if el.CanSeeHidden() {
+		names = append(names, "see hidden")
+	}
+	if el.CanSeeInvisible() {
+		names = append(names, "see invisible")
+	}
+	if el.GrantHidden() {
+		names = append(names, "hiding")
+	}
+	if el.GrantInvisible() {
+		names = append(names, "invisibility")
+	}
+	return names
+}
@@ -0,0 +1,88 @@
+package mud
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// BaseCarryWeight is the weight any character can carry before counting Strength and race
+	BaseCarryWeight = 30
+	// CarryWeightPerStrength is how much more weight each point of Strength allows to carry
+	CarryWeightPerStrength = 5
+	// MinCarryWeight is the lowest carry capacity a character can have, no matter how weak
+	MinCarryWeight = 10
+	// EncumberedPercent is the percentage of the carry capacity above which the character is encumbered
+	EncumberedPercent = 75
+	// EncumberedMoveDelay is how long an encumbered character has to wait between one move and the next
+	EncumberedMoveDelay = 3 * time.Second
+	// EncumberedDexterityPenalty is how much Dexterity an encumbered character loses in combat
+	EncumberedDexterityPenalty = 2
+)
+
+// CarriedWeight returns the total weight of the inventory, including what is inside the containers, and the equipment
+func (p *Player) CarriedWeight() int {
+	weight := 0
+	for _, item := range p.Inventory {
+		weight += item.TotalWeight()
+	}
+	for _, item := range p.Equip {
+		weight += item.TotalWeight()
+	}
+	return weight
+}
+
+// CarryCapacity returns the maximum weight the player can carry, given by Strength and race
+func (p *Player) CarryCapacity() int {
+	capacity := BaseCarryWeight + CarryWeightPerStrength*p.GetCurrentStat(Strength) + p.carryBonus()
+	return max(capacity, MinCarryWeight)
+}
+
+// carryBonus returns how much more weight the race of the player can carry
+func (p *Player) carryBonus() int {
+	if p.Race == nil {
+		return 0
+	}
+	return p.Race.CarryBonus
+}
+
+// CanCarry returns whether the player can pick up the item without going over the carry capacity
+func (p *Player) CanCarry(item *Item) bool {
+	return p.CarriedWeight()+item.TotalWeight() <= p.CarryCapacity()
+}
+
+// IsEncumbered returns whether the player carries so much that it slows the movement and hinders the combat
+func (p *Player) IsEncumbered() bool {
+	return p.CarriedWeight()*100 > p.CarryCapacity()*EncumberedPercent
+}
+
+// GetCombatStat returns the stat of the player as used in combat, after the penalties for being encumbered.
+// Stunned players cannot dodge at all.
+func (p *Player) GetCombatStat(s Stat) int {
+	if s == Dexterity && p.Position == Stunned {
+		return MinStat
+	}
+	stat := p.GetCurrentStat(s)
+	if s == Dexterity && p.IsEncumbered() {
+		stat -= EncumberedDexterityPenalty
+	}
+	return stat
+}
+
+// ShowWeight returns how much the player carries out of the carry capacity, as shown on the inventory
+func (p *Player) ShowWeight() string {
+	weight := fmt.Sprintf("Weight: %d/%d", p.CarriedWeight(), p.CarryCapacity())
+	if p.IsEncumbered() {
+		weight += " (encumbered)"
+	}
+	return weight
+}
+
+// tooTired returns whether the player is encumbered and moved too recently to move again, notifying the player if so
+func (p *Player) tooTired() bool {
+	if !p.IsEncumbered() || time.Since(p.lastMove) >= EncumberedMoveDelay {
+		return false
+	}
+	p.Notify("You are carrying too much to move that fast. Catch your breath for a moment.")
+	return true
+}
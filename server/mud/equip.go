@@ -1,10 +1,15 @@
 package mud
 
+import (
+	"fmt"
+	"strings"
+)
+
 // EquipmentSlot represents each slot of equipment
 type EquipmentSlot int
 
 // PlayerEquipment stores all the equipped items from a player
-type PlayerEquipment map[EquipmentSlot]*Equipment
+type PlayerEquipment map[EquipmentSlot]*Item
 
 const (
 	// Head represents any item that can be wear on the head, like helmets, masks or caps
@@ -27,14 +32,65 @@ const (
 	LeftRing
 )
 
+// equipmentSlotNames maps each slot to the name used on the JSON files
+var equipmentSlotNames = map[EquipmentSlot]string{
+	Head:      "head",
+	Chest:     "chest",
+	Legs:      "legs",
+	Feet:      "feet",
+	RightHand: "right_hand",
+	LeftHand:  "left_hand",
+	Necklace:  "necklace",
+	RightRing: "right_ring",
+	LeftRing:  "left_ring",
+}
+
+// MarshalText marshals the slot into its name
+func (s EquipmentSlot) MarshalText() ([]byte, error) {
+	name, ok := equipmentSlotNames[s]
+	if !ok {
+		return nil, fmt.Errorf("unknown equipment slot %d", s)
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText unmarshals the slot from its name
+func (s *EquipmentSlot) UnmarshalText(b []byte) error {
+	for k, v := range equipmentSlotNames {
+		if v == string(b) {
+			*s = k
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown equipment slot %s", string(b))
+}
+
+// String returns the name of the slot as shown to the players, like left hand
+func (s EquipmentSlot) String() string {
+	return strings.Replace(equipmentSlotNames[s], "_", " ", -1)
+}
+
+// Show returns one line per equipped item, sorted by slot. Example: head: a leather cap
+func (e PlayerEquipment) Show() []string {
+	lines := []string{}
+	for slot := Head; slot <= LeftRing; slot++ {
+		item, ok := e[slot]
+		if !ok {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", slot, item.Name))
+	}
+	return lines
+}
+
 // GetRightAttack gets the attack of your right hand weapon
 func (e PlayerEquipment) GetRightAttack() int {
-	return e[RightHand].GetAttack()
+	return e[RightHand].equipment().GetAttack()
 }
 
 // GetLeftAttack gets the attack of your left hand weapon
 func (e PlayerEquipment) GetLeftAttack() int {
-	return e[LeftHand].GetAttack()
+	return e[LeftHand].equipment().GetAttack()
 }
 
 // GetAttackModifiers gets all attack modifiers from the equipment
@@ -44,7 +100,7 @@ func (e PlayerEquipment) GetAttackModifiers() int {
 		if k == RightHand || k == LeftHand {
 			continue
 		}
-		modifier += v.GetAttack()
+		modifier += v.equipment().GetAttack()
 	}
 	return modifier
 }
@@ -53,7 +109,7 @@ func (e PlayerEquipment) GetAttackModifiers() int {
 func (e PlayerEquipment) GetStatModifiers(s Stat) int {
 	modifier := 0
 	for _, v := range e {
-		modifier += v.GetStat(s)
+		modifier += v.equipment().GetStat(s)
 	}
 	return modifier
 }
@@ -61,7 +117,7 @@ func (e PlayerEquipment) GetStatModifiers(s Stat) int {
 // CanSeeInvisible returns whether any piece of equipment lets you see the invisible
 func (e PlayerEquipment) CanSeeInvisible() bool {
 	for _, v := range e {
-		if v.CanSeeInvisible() {
+		if v.equipment().CanSeeInvisible() {
 			return true
 		}
 	}
@@ -71,7 +127,7 @@ func (e PlayerEquipment) CanSeeInvisible() bool {
 // CanSeeHidden returns whether any piece of equipment lets you see hidden objects
 func (e PlayerEquipment) CanSeeHidden() bool {
 	for _, v := range e {
-		if v.CanSeeHidden() {
+		if v.equipment().CanSeeHidden() {
 			return true
 		}
 	}
@@ -81,7 +137,7 @@ func (e PlayerEquipment) CanSeeHidden() bool {
 // GrantInvisible returns whether any piece of equipment renders you invisible
 func (e PlayerEquipment) GrantInvisible() bool {
 	for _, v := range e {
-		if v.GrantInvisible() {
+		if v.equipment().GrantInvisible() {
 			return true
 		}
 	}
@@ -91,24 +147,34 @@ func (e PlayerEquipment) GrantInvisible() bool {
 // GrantHidden returns whether any piece of equipment renders you hidden
 func (e PlayerEquipment) GrantHidden() bool {
 	for _, v := range e {
-		if v.GrantHidden() {
+		if v.equipment().GrantHidden() {
 			return true
 		}
 	}
 	return false
 }
 
-// Equipment represents a single piece of equipment
+// Equipment represents the properties of a piece of equipment
 type Equipment struct {
-	Item
 	// Slot denotes where it is wear. For wielded items will always denote RightHand even if it can be wielded on both hands. Same for rings.
-	Slot EquipmentSlot
+	Slot EquipmentSlot `json:"slot"`
 	// StatsModifiers denotes how much modify each stat
-	StatsModifiers Stats
+	StatsModifiers Stats `json:"stats_modifiers"`
 	// Attack denotes how much attack it grants
-	Attack int
+	Attack int `json:"attack"`
 	// MagicEffects denotes all the magical effects this item has
-	MagicEffects EffectList
+	MagicEffects EffectList `json:"magic_effects"`
+}
+
+// spawn returns a copy of the equipment that can be modified without changing the template
+func (e *Equipment) spawn() *Equipment {
+	newEquipment := *e
+	newEquipment.StatsModifiers = make(Stats)
+	for s, modifier := range e.StatsModifiers {
+		newEquipment.StatsModifiers[s] = modifier
+	}
+	newEquipment.MagicEffects = append(EffectList{}, e.MagicEffects...)
+	return &newEquipment
 }
 
 // GetAttack returns the attack of the item
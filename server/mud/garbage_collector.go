@@ -26,6 +26,7 @@ func (w *World) garbageCollector() {
 				return
 			}
 			t := time.Now()
+			room.rotItems(t)
 			for k, v := range room.shouts {
 				if t.Unix() < v.Add(ShoutLifespan).Unix() {
 					delete(room.shouts, k)
@@ -0,0 +1,68 @@
+package mud
+
+import (
+	"fmt"
+)
+
+// Gender represents the grammatical gender used to refer to a character
+type Gender int
+
+const (
+	// Neutral characters are referred to as "it". It is the default for mobs
+	Neutral Gender = iota
+	// Male characters are referred to as "he"
+	Male
+	// Female characters are referred to as "she"
+	Female
+)
+
+// genderNames maps each gender to the name used on the JSON files
+var genderNames = map[Gender]string{
+	Neutral: "neutral",
+	Male:    "male",
+	Female:  "female",
+}
+
+// MarshalText marshals the gender into its name
+func (g Gender) MarshalText() ([]byte, error) {
+	name, ok := genderNames[g]
+	if !ok {
+		return nil, fmt.Errorf("unknown gender %d", g)
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText unmarshals the gender from its name
+func (g *Gender) UnmarshalText(b []byte) error {
+	for k, v := range genderNames {
+		if v == string(b) {
+			*g = k
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown gender %s", string(b))
+}
+
+// Subject returns the subject pronoun for the gender. Example: she
+func (g Gender) Subject() string {
+	switch g {
+	case Male:
+		return "he"
+	case Female:
+		return "she"
+	default:
+		return "it"
+	}
+}
+
+// Possessive returns the possessive determiner for the gender. Example: her
+func (g Gender) Possessive() string {
+	switch g {
+	case Male:
+		return "his"
+	case Female:
+		return "her"
+	default:
+		return "its"
+	}
+}
@@ -1,5 +1,186 @@
 package mud
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 // Item represent one item in the game
 type Item struct {
+	// ID is the unique identifier of the item template
+	ID string `json:"id"`
+	// Name is the name shown to the player, including the article. Example: a rusty key
+	Name string `json:"name"`
+	// Description is shown to the player when looking at the item
+	Description string `json:"description"`
+	// Keywords are the words the player can use to refer to the item. The ID is always a keyword
+	Keywords []string `json:"keywords"`
+	// Value is how much gold the item is worth when bought or sold in shops
+	Value int `json:"value"`
+	// Weight is how heavy the item is, without counting what it contains
+	Weight int `json:"weight"`
+	// NoTake denotes that the item cannot be picked up from the ground, like chests and corpses
+	NoTake bool `json:"no_take"`
+	// Equipment contains the properties of the item when worn or wielded. Empty for items that cannot be equipped
+	Equipment *Equipment `json:"equipment,omitempty"`
+	// Container contains the properties of the item when it can hold other items. Empty for the rest of items
+	Container *Container `json:"container,omitempty"`
+	// Consumable contains the properties of the item when it can be quaffed, read, eaten, drunk or used. Empty for the rest of items
+	Consumable *Consumable `json:"consumable,omitempty"`
+	// RandomAffixes denotes that the equipment rolls a rarity and random affixes when dropped by a mob
+	RandomAffixes bool `json:"random_affixes"`
+	// Rarity is the rarity rolled for the item. Item templates cannot define it, it is rolled when dropped
+	Rarity Rarity `json:"rarity,omitempty"`
+	// Affixes are the affixes rolled for the item, already added to its equipment and name. Item templates cannot define them
+	Affixes []*Affix `json:"affixes,omitempty"`
+	// decayAt is when the item rots away, like corpses. Zero means never
+	decayAt time.Time
+	// affixes are the affixes the template can roll, given by the slot of its equipment
+	affixes []*Affix
+	// ownerID is the user ID of the only player who can take things out of the item, like the corpse of a dead player. Empty means anyone
+	ownerID string
+}
+
+// ItemList represents a list of items
+type ItemList []*Item
+
+// Spawn creates a new item using another item as template
+func (i *Item) Spawn() *Item {
+	newItem := *i
+	if i.Container != nil {
+		newItem.Container = i.Container.spawn()
+	}
+	if i.Consumable != nil {
+		consumable := *i.Consumable
+		newItem.Consumable = &consumable
+	}
+	return &newItem
+}
+
+// TotalWeight returns the weight of the item along with everything it contains
+func (i *Item) TotalWeight() int {
+	if i.Container == nil {
+		return i.Weight
+	}
+	return i.Weight + i.Container.Weight()
+}
+
+// Matches returns whether the player may refer to this item by keyword
+func (i *Item) Matches(keyword string) bool {
+	keyword = strings.ToLower(keyword)
+	if keyword == strings.ToLower(i.ID) {
+		return true
+	}
+	for _, v := range i.Keywords {
+		if keyword == strings.ToLower(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Show returns the string of how the item is seen on the ground
+func (i *Item) Show() string {
+	return capitalize(i.Name) + " is lying here."
+}
+
+// Look returns the description shown when a player looks at the item
+func (i *Item) Look() string {
+	if i.Description == "" {
+		return fmt.Sprintf("You see nothing special about %s.", i.Name)
+	}
+	return i.Description
+}
+
+// Examine returns the description of the item along with all its properties
+func (i *Item) Examine() string {
+	lines := []string{capitalize(i.Name), "", i.Look()}
+	if i.Rarity != Common {
+		lines = append(lines, "", "Rarity: "+i.Rarity.String())
+	}
+	if i.Container != nil {
+		lines = append(lines, "")
+		lines = append(lines, i.Container.examine()...)
+	}
+	if i.Consumable != nil {
+		lines = append(lines, "")
+		lines = append(lines, i.Consumable.examine()...)
+	}
+	if i.Equipment == nil {
+		return strings.Join(lines, "\n")
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, "Worn on: "+i.Equipment.Slot.String())
+	if i.Equipment.Attack != 0 {
+		lines = append(lines, fmt.Sprintf("Attack: %+d", i.Equipment.Attack))
+	}
+	for s := Stat(0); s < StatsLength; s++ {
+		if modifier := i.Equipment.StatsModifiers[s]; modifier != 0 {
+			lines = append(lines, fmt.Sprintf("%s: %+d", s, modifier))
+		}
+	}
+	if effects := i.Equipment.MagicEffects.Names(); len(effects) > 0 {
+		lines = append(lines, "Magic: "+strings.Join(effects, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// equipment returns the equipment properties of the item, or nil if it cannot be equipped
+func (i *Item) equipment() *Equipment {
+	if i == nil {
+		return nil
+	}
+	return i.Equipment
+}
+
+// Find returns the first item matching the keyword, or nil if there is no such item
+func (il ItemList) Find(keyword string) *Item {
+	for _, v := range il {
+		if v.Matches(keyword) {
+			return v
+		}
+	}
+	return nil
+}
+
+// FindID returns the first item with certain ID, or nil if there is no such item
+func (il ItemList) FindID(itemID string) *Item {
+	for _, v := range il {
+		if v.ID == itemID {
+			return v
+		}
+	}
+	return nil
+}
+
+// Count returns how many items with certain ID are on the list
+func (il ItemList) Count(itemID string) int {
+	count := 0
+	for _, v := range il {
+		if v.ID == itemID {
+			count++
+		}
+	}
+	return count
+}
+
+// Remove returns the list without the item
+func (il ItemList) Remove(item *Item) ItemList {
+	for i, v := range il {
+		if v == item {
+			return append(il[:i], il[i+1:]...)
+		}
+	}
+	return il
+}
+
+// Names returns the names of all the items on the list
+func (il ItemList) Names() []string {
+	names := make([]string, 0, len(il))
+	for _, v := range il {
+		names = append(names, v.Name)
+	}
+	return names
 }
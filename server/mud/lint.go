@@ -0,0 +1,900 @@
+package mud
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LintSeverity denotes how serious a problem found on the asset files is
+type LintSeverity int
+
+const (
+	// LintWarning denotes something that is probably a mistake, but does not prevent the world from loading
+	LintWarning LintSeverity = iota
+	// LintError denotes something that prevents the world from loading
+	LintError
+)
+
+func (s LintSeverity) String() string {
+	if s == LintError {
+		return "error"
+	}
+	return "warning"
+}
+
+// LintProblem is a single problem found on the asset files
+type LintProblem struct {
+	// Severity denotes how serious the problem is
+	Severity LintSeverity
+	// File is the path of the file where the problem was found
+	File string
+	// Path is the JSON path inside the file where the problem was found
+	Path string
+	// Message describes the problem
+	Message string
+}
+
+func (p *LintProblem) String() string {
+	return fmt.Sprintf("%s: %s: %s: %s", p.File, p.Path, p.Severity, p.Message)
+}
+
+// lintRoom stores where a room was defined, to be able to point at it when checking the links between rooms
+type lintRoom struct {
+	file  string
+	path  string
+	room  *JSONRoom
+	exits map[Direction]*lintExit
+}
+
+// lintExit stores where a transition between rooms was defined
+type lintExit struct {
+	path     string
+	roomID   string
+	external bool
+}
+
+// lintTeleport stores where a teleport to a room was defined, to check the room once all the areas are read
+type lintTeleport struct {
+	file   string
+	path   string
+	roomID string
+}
+
+// lintQuestTarget stores where a quest objective refers to a room or an NPC, to check it once all the areas are read
+type lintQuestTarget struct {
+	file string
+	path string
+	// id is the full ID of the room or NPC
+	id string
+	// npc denotes that the target is an NPC instead of a room
+	npc bool
+}
+
+// lintKey stores where a key of a container was defined, to check it once all the items are read
+type lintKey struct {
+	file   string
+	path   string
+	itemID string
+}
+
+// assetLinter collects all the problems found while going through the asset files
+type assetLinter struct {
+	problems []*LintProblem
+	items    map[string]*Item
+	mobs     map[string]string
+	quests   map[string]string
+	areas    map[string]string
+	races    map[string]string
+	classes  map[string]string
+	affixes  []*Affix
+	npcs     map[string]bool
+	rooms    map[string]*lintRoom
+	// startRooms lists the recall rooms of the areas flagged as start
+	startRooms []string
+	// roomOrder keeps the rooms in the order they were read, so the output is stable
+	roomOrder []string
+	// teleports lists the teleports of the NPC dialogues
+	teleports []*lintTeleport
+	// questTargets lists the rooms and NPCs the quest objectives refer to
+	questTargets []*lintQuestTarget
+	// keys lists the keys of the containers
+	keys []*lintKey
+}
+
+// LintAssets goes through all the asset files under assetsPath and returns all the problems found.
+// startRoom is the room from where all the other rooms should be reachable. If empty, the recall room of the area flagged as start is used.
+func LintAssets(assetsPath, startRoom string) []*LintProblem {
+	l := &assetLinter{
+		items:   make(map[string]*Item),
+		mobs:    make(map[string]string),
+		quests:  make(map[string]string),
+		areas:   make(map[string]string),
+		races:   make(map[string]string),
+		classes: make(map[string]string),
+		npcs:    make(map[string]bool),
+		rooms:   make(map[string]*lintRoom),
+	}
+
+	l.walk(filepath.Join(assetsPath, "races.json"), l.lintRaceFile)
+	l.walk(filepath.Join(assetsPath, "classes.json"), l.lintClassFile)
+	l.walk(filepath.Join(assetsPath, "affixes.json"), l.lintAffixFile)
+	l.walk(filepath.Join(assetsPath, "items"), l.lintItemFile)
+	l.lintKeys()
+	l.walk(filepath.Join(assetsPath, "mobs"), l.lintMobFile)
+	l.walk(filepath.Join(assetsPath, "quests"), l.lintQuestFile)
+	l.walk(filepath.Join(assetsPath, "areas"), l.lintAreaFile)
+	l.lintNeighbours()
+	l.lintTeleports()
+	l.lintQuestTargets()
+
+	switch {
+	case startRoom != "":
+		l.lintReachability(startRoom)
+	case len(l.startRooms) == 0:
+		l.report(LintError, "", "", "no area is flagged as %s", AreaFlagStart)
+	case len(l.startRooms) > 1:
+		l.report(LintError, "", "", "%d areas are flagged as %s, only one is allowed", len(l.startRooms), AreaFlagStart)
+	default:
+		l.lintReachability(l.startRooms[0])
+	}
+
+	return l.problems
+}
+
+func (l *assetLinter) report(severity LintSeverity, file, path, format string, args ...interface{}) {
+	l.problems = append(l.problems, &LintProblem{
+		Severity: severity,
+		File:     file,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *assetLinter) walk(dir string, lintFile func(path string, file *os.File)) {
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			l.report(LintError, path, "$", "cannot open file: %s", err.Error())
+			return nil
+		}
+		defer file.Close()
+
+		lintFile(path, file)
+		return nil
+	})
+	if err != nil {
+		l.report(LintError, dir, "$", "cannot read directory: %s", err.Error())
+	}
+}
+
+func (l *assetLinter) reportDecodeError(file string, err error) {
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		l.report(LintError, file, "$", "invalid JSON at offset %d: %s", e.Offset, e.Error())
+	case *json.UnmarshalTypeError:
+		l.report(LintError, file, "$."+e.Field, "expected %s but found %s", e.Type.String(), e.Value)
+	default:
+		l.report(LintError, file, "$", "cannot decode file: %s", err.Error())
+	}
+}
+
+func (l *assetLinter) lintRaceFile(path string, file *os.File) {
+	var races []*Race
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&races); err != nil {
+		l.reportDecodeError(path, err)
+		return
+	}
+
+	for i, r := range races {
+		l.lintTraits(path, fmt.Sprintf("$[%d]", i), "race", &r.Traits, l.races)
+	}
+	if _, ok := l.races[DefaultRace]; !ok {
+		l.report(LintError, path, "$", "default race '%s' is not defined", DefaultRace)
+	}
+}
+
+func (l *assetLinter) lintClassFile(path string, file *os.File) {
+	var classes []*Class
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&classes); err != nil {
+		l.reportDecodeError(path, err)
+		return
+	}
+
+	for i, c := range classes {
+		l.lintTraits(path, fmt.Sprintf("$[%d]", i), "class", &c.Traits, l.classes)
+	}
+	if _, ok := l.classes[DefaultClass]; !ok {
+		l.report(LintError, path, "$", "default class '%s' is not defined", DefaultClass)
+	}
+}
+
+// lintTraits checks the traits shared by races and classes, registering the ID on seen
+func (l *assetLinter) lintTraits(path, traitsPath, kind string, t *Traits, seen map[string]string) {
+	if t.ID == "" {
+		l.report(LintError, path, traitsPath+".id", "missing %s id", kind)
+		return
+	}
+	if previous, ok := seen[t.ID]; ok {
+		l.report(LintError, path, traitsPath+".id", "%s id '%s' duplicated, first defined at %s", kind, t.ID, previous)
+		return
+	}
+	seen[t.ID] = path + ":" + traitsPath
+	if t.Name == "" {
+		l.report(LintError, path, traitsPath+".name", "%s '%s' has no name", kind, t.ID)
+	}
+	if t.Description == "" {
+		l.report(LintWarning, path, traitsPath+".description", "%s '%s' has no description", kind, t.ID)
+	}
+	if t.ManaPerLevel < 0 {
+		l.report(LintError, path, traitsPath+".mana_per_level", "%s '%s' cannot lose mana on each level", kind, t.ID)
+	}
+	for i, skill := range t.Skills {
+		if _, ok := spellCosts[skill]; !ok {
+			l.report(LintError, path, fmt.Sprintf("%s.skills[%d]", traitsPath, i), "unknown skill '%s'", skill)
+		}
+	}
+}
+
+func (l *assetLinter) lintItemFile(path string, file *os.File) {
+	var items []*Item
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&items); err != nil {
+		l.reportDecodeError(path, err)
+		return
+	}
+
+	for i, item := range items {
+		itemPath := fmt.Sprintf("$[%d]", i)
+		if item.ID == "" {
+			l.report(LintError, path, itemPath+".id", "missing item id")
+			continue
+		}
+		if _, ok := l.items[item.ID]; ok {
+			l.report(LintError, path, itemPath+".id", "item id '%s' duplicated", item.ID)
+			continue
+		}
+		l.items[item.ID] = item
+		if item.Name == "" {
+			l.report(LintError, path, itemPath+".name", "item '%s' has no name", item.ID)
+		}
+		if item.Description == "" {
+			l.report(LintWarning, path, itemPath+".description", "item '%s' has no description", item.ID)
+		}
+		if item.Value < 0 {
+			l.report(LintError, path, itemPath+".value", "item '%s' cannot have negative value", item.ID)
+		}
+		if item.Weight < 0 {
+			l.report(LintError, path, itemPath+".weight", "item '%s' cannot have negative weight", item.ID)
+		}
+		if item.Container != nil {
+			l.lintContainer(path, itemPath+".container", item)
+		}
+		if item.Rarity != Common || len(item.Affixes) > 0 {
+			l.report(LintError, path, itemPath, "item '%s' cannot define its rarity nor its affixes, they are rolled when dropped", item.ID)
+		}
+		if item.RandomAffixes {
+			l.lintRandomAffixes(path, itemPath+".random_affixes", item)
+		}
+		if item.Consumable != nil {
+			l.lintConsumable(path, itemPath+".consumable", item)
+		}
+	}
+}
+
+// lintConsumable checks that a consumable does something, and that only wands and staves have charges
+func (l *assetLinter) lintConsumable(path, consumablePath string, item *Item) {
+	c := item.Consumable
+	if item.Equipment != nil {
+		l.report(LintError, path, consumablePath, "item '%s' cannot be both equipment and consumable", item.ID)
+	}
+	if c.Heal < 0 {
+		l.report(LintError, path, consumablePath+".heal", "item '%s' cannot heal negative HP", item.ID)
+	}
+	if c.Heal <= 0 && len(c.Effects) == 0 && !c.Recall {
+		l.report(LintWarning, path, consumablePath, "consuming item '%s' does nothing", item.ID)
+	}
+	if len(c.Effects) > 0 && c.Duration <= 0 {
+		l.report(LintError, path, consumablePath+".duration", "effects of item '%s' must last a positive number of minutes", item.ID)
+	}
+	for i, e := range c.Effects {
+		if e.Name == "" {
+			l.report(LintWarning, path, fmt.Sprintf("%s.effects[%d].name", consumablePath, i), "effect of item '%s' has no name, players will not be told when it wears off", item.ID)
+		}
+	}
+	switch {
+	case c.hasCharges() && c.Charges <= 0:
+		l.report(LintError, path, consumablePath+".charges", "%s '%s' must have a positive number of charges", consumableKindNames[c.Kind], item.ID)
+	case !c.hasCharges() && c.Charges != 0:
+		l.report(LintWarning, path, consumablePath+".charges", "only wands and staves have charges, item '%s' is used up at once", item.ID)
+	}
+}
+
+// lintRandomAffixes checks that an item rolling random affixes is equipment that can get some of them
+func (l *assetLinter) lintRandomAffixes(path, affixesPath string, item *Item) {
+	if item.Equipment == nil {
+		l.report(LintError, path, affixesPath, "item '%s' cannot roll affixes, it is not equipment", item.ID)
+		return
+	}
+	for _, a := range l.affixes {
+		if a.fits(item.Equipment.Slot) {
+			return
+		}
+	}
+	l.report(LintWarning, path, affixesPath, "no affix fits the %s of item '%s', it will always be common", item.Equipment.Slot, item.ID)
+}
+
+func (l *assetLinter) lintAffixFile(path string, file *os.File) {
+	var affixes []*Affix
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&affixes); err != nil {
+		l.reportDecodeError(path, err)
+		return
+	}
+
+	seen := make(map[string]bool)
+	for i, a := range affixes {
+		affixPath := fmt.Sprintf("$[%d]", i)
+		if a.ID == "" {
+			l.report(LintError, path, affixPath+".id", "missing affix id")
+			continue
+		}
+		if seen[a.ID] {
+			l.report(LintError, path, affixPath+".id", "affix id '%s' duplicated", a.ID)
+			continue
+		}
+		seen[a.ID] = true
+		l.affixes = append(l.affixes, a)
+		if a.Name == "" {
+			l.report(LintError, path, affixPath+".name", "affix '%s' has no name", a.ID)
+		}
+		if a.Attack == 0 && len(a.MagicEffects) == 0 && !a.hasStatsModifiers() {
+			l.report(LintWarning, path, affixPath, "affix '%s' does not change the item", a.ID)
+		}
+	}
+}
+
+// lintContainer checks the limits and the lock of a container. Keys are checked once all the items are read
+func (l *assetLinter) lintContainer(path, containerPath string, item *Item) {
+	c := item.Container
+	if c.Capacity < 0 || c.MaxWeight < 0 {
+		l.report(LintError, path, containerPath, "capacity and max weight of item '%s' cannot be negative", item.ID)
+	}
+	if len(c.Contents) > 0 {
+		l.report(LintError, path, containerPath+".contents", "item '%s' cannot define its contents, use put resets instead", item.ID)
+	}
+	if (c.IsClosed || c.IsLocked) && !c.Closeable {
+		l.report(LintError, path, containerPath+".closeable", "item '%s' is closed but cannot be opened", item.ID)
+	}
+	if c.IsLocked && !c.IsClosed {
+		l.report(LintError, path, containerPath+".closed", "item '%s' is locked but not closed", item.ID)
+	}
+	if c.Key != "" {
+		l.keys = append(l.keys, &lintKey{file: path, path: containerPath + ".key", itemID: c.Key})
+	}
+}
+
+func (l *assetLinter) lintMobFile(path string, file *os.File) {
+	var mobs []*Mob
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&mobs); err != nil {
+		l.reportDecodeError(path, err)
+		return
+	}
+
+	for i, mob := range mobs {
+		mobPath := fmt.Sprintf("$[%d]", i)
+		if mob.ID == "" {
+			l.report(LintError, path, mobPath+".id", "missing mob id")
+			continue
+		}
+		if previous, ok := l.mobs[mob.ID]; ok {
+			l.report(LintError, path, mobPath+".id", "mob id '%s' duplicated, first defined at %s", mob.ID, previous)
+			continue
+		}
+		l.mobs[mob.ID] = path + ":" + mobPath
+		if mob.MaxHP <= 0 {
+			l.report(LintError, path, mobPath+".max_hp", "mob '%s' must have positive max HP", mob.ID)
+		}
+		for j, flag := range mob.Flags {
+			if !knownMobFlags[flag] {
+				l.report(LintError, path, fmt.Sprintf("%s.flags[%d]", mobPath, j), "unknown flag '%s' for mob '%s'", flag, mob.ID)
+			}
+		}
+		if mob.HasFlag(MobFlagWanderer) && mob.HasFlag(MobFlagSentinel) {
+			l.report(LintWarning, path, mobPath+".flags", "mob '%s' is both wanderer and sentinel, it will never move", mob.ID)
+		}
+		if mob.Gold < 0 {
+			l.report(LintError, path, mobPath+".gold", "mob '%s' cannot carry negative gold", mob.ID)
+		}
+		if mob.Name == "" {
+			l.report(LintWarning, path, mobPath+".name", "mob '%s' has no name, the id will be shown", mob.ID)
+		}
+		if mob.Description == "" {
+			l.report(LintWarning, path, mobPath+".description", "mob '%s' has no description", mob.ID)
+		}
+		for j, d := range mob.Drops {
+			if _, ok := l.items[d.ItemID]; !ok {
+				l.report(LintError, path, fmt.Sprintf("%s.drops[%d].item_id", mobPath, j), "unknown item id '%s'", d.ItemID)
+			}
+		}
+	}
+}
+
+func (l *assetLinter) lintQuestFile(path string, file *os.File) {
+	var quests []*Quest
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&quests); err != nil {
+		l.reportDecodeError(path, err)
+		return
+	}
+
+	for i, q := range quests {
+		questPath := fmt.Sprintf("$[%d]", i)
+		if q.ID == "" {
+			l.report(LintError, path, questPath+".id", "missing quest id")
+			continue
+		}
+		if previous, ok := l.quests[q.ID]; ok {
+			l.report(LintError, path, questPath+".id", "quest id '%s' duplicated, first defined at %s", q.ID, previous)
+			continue
+		}
+		l.quests[q.ID] = path + ":" + questPath
+		if q.Name == "" {
+			l.report(LintWarning, path, questPath+".name", "quest '%s' has no name, the id will be shown", q.ID)
+		}
+		if q.Description == "" {
+			l.report(LintWarning, path, questPath+".description", "quest '%s' has no description", q.ID)
+		}
+		if len(q.Objectives) == 0 {
+			l.report(LintError, path, questPath+".objectives", "quest '%s' has no objectives", q.ID)
+		}
+		for j, o := range q.Objectives {
+			l.lintObjective(path, fmt.Sprintf("%s.objectives[%d]", questPath, j), o)
+		}
+		if q.Reward.Experience < 0 || q.Reward.Gold < 0 {
+			l.report(LintError, path, questPath+".reward", "quest '%s' cannot reward negative experience or gold", q.ID)
+		}
+		for j, id := range q.Reward.Items {
+			if l.items[id] == nil {
+				l.report(LintError, path, fmt.Sprintf("%s.reward.items[%d]", questPath, j), "unknown item id '%s'", id)
+			}
+		}
+	}
+}
+
+// lintObjective checks that a quest objective refers to existing mobs and items, deferring the rooms and NPCs until the areas are read
+func (l *assetLinter) lintObjective(path, objectivePath string, o *Objective) {
+	switch o.Type {
+	case ObjectiveKill:
+		if _, ok := l.mobs[o.MobID]; !ok {
+			l.report(LintError, path, objectivePath+".mob_id", "unknown mob id '%s'", o.MobID)
+		}
+		if o.Count < 0 {
+			l.report(LintError, path, objectivePath+".count", "count cannot be negative")
+		}
+	case ObjectiveReach:
+		l.questTargets = append(l.questTargets, &lintQuestTarget{file: path, path: objectivePath + ".room", id: o.Room})
+	case ObjectiveDeliver:
+		if l.items[o.ItemID] == nil {
+			l.report(LintError, path, objectivePath+".item_id", "unknown item id '%s'", o.ItemID)
+		}
+		l.questTargets = append(l.questTargets, &lintQuestTarget{file: path, path: objectivePath + ".npc", id: o.NPC, npc: true})
+	case ObjectiveTalk:
+		l.questTargets = append(l.questTargets, &lintQuestTarget{file: path, path: objectivePath + ".npc", id: o.NPC, npc: true})
+	default:
+		l.report(LintError, path, objectivePath+".type", "unknown objective type '%s'", o.Type)
+	}
+}
+
+func (l *assetLinter) lintAreaFile(path string, file *os.File) {
+	var area JSONArea
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&area); err != nil {
+		l.reportDecodeError(path, err)
+		return
+	}
+
+	if area.ID == "" {
+		l.report(LintError, path, "$.area_id", "missing area id")
+		return
+	}
+	if previous, ok := l.areas[area.ID]; ok {
+		l.report(LintError, path, "$.area_id", "area id '%s' duplicated, first defined at %s", area.ID, previous)
+		return
+	}
+	l.areas[area.ID] = path
+
+	if area.Name == "" {
+		l.report(LintWarning, path, "$.name", "area '%s' has no name", area.ID)
+	}
+	if area.MinLevel < 0 || area.MaxLevel < 0 {
+		l.report(LintError, path, "$.min_level", "levels cannot be negative")
+	}
+	if area.MaxLevel > 0 && area.MinLevel > area.MaxLevel {
+		l.report(LintError, path, "$.min_level", "min level %d is higher than max level %d", area.MinLevel, area.MaxLevel)
+	}
+	if area.ResetInterval < 0 {
+		l.report(LintError, path, "$.reset_interval", "reset interval cannot be negative")
+	}
+	for i, flag := range area.Flags {
+		if !knownAreaFlags[flag] {
+			l.report(LintError, path, fmt.Sprintf("$.flags[%d]", i), "unknown area flag '%s'", flag)
+		}
+		if flag == AreaFlagStart {
+			l.startRooms = append(l.startRooms, area.ID+"_"+area.RecallRoom)
+		}
+	}
+
+	recallFound := false
+	for i, room := range area.Rooms {
+		roomPath := fmt.Sprintf("$.rooms[%d]", i)
+		if room.ID == "" {
+			l.report(LintError, path, roomPath+".id", "missing room id")
+			continue
+		}
+		roomID := area.ID + "_" + room.ID
+		if previous, ok := l.rooms[roomID]; ok {
+			l.report(LintError, path, roomPath+".id", "room id '%s' duplicated, first defined at %s:%s", roomID, previous.file, previous.path)
+			continue
+		}
+		room.AreaID = area.ID
+		recallFound = recallFound || room.ID == area.RecallRoom
+		lr := &lintRoom{
+			file:  path,
+			path:  roomPath,
+			room:  room,
+			exits: make(map[Direction]*lintExit),
+		}
+		l.rooms[roomID] = lr
+		l.roomOrder = append(l.roomOrder, roomID)
+
+		if room.Name == "" {
+			l.report(LintError, path, roomPath+".name", "room '%s' has no name", roomID)
+		}
+		if room.ShortDescription == "" {
+			l.report(LintError, path, roomPath+".short_description", "room '%s' has no short description", roomID)
+		}
+		if room.LongDescription == "" {
+			l.report(LintWarning, path, roomPath+".long_description", "room '%s' has no long description", roomID)
+		}
+		for j, flag := range room.Flags {
+			if !knownRoomFlags[flag] {
+				l.report(LintError, path, fmt.Sprintf("%s.flags[%d]", roomPath, j), "unknown room flag '%s'", flag)
+			}
+		}
+		for j, extra := range room.ExtraDescriptions {
+			extraPath := fmt.Sprintf("%s.extra_descriptions[%d]", roomPath, j)
+			if len(extra.Keywords) == 0 {
+				l.report(LintError, path, extraPath+".keywords", "extra description of room '%s' has no keywords", roomID)
+			}
+			if extra.Description == "" {
+				l.report(LintError, path, extraPath+".description", "extra description of room '%s' has no description", roomID)
+			}
+		}
+
+		directions := make([]string, 0, len(room.Neighbours))
+		for direction := range room.Neighbours {
+			directions = append(directions, direction)
+		}
+		sort.Strings(directions)
+		for _, direction := range directions {
+			door := room.Neighbours[direction]
+			doorPath := fmt.Sprintf("%s.neighbours.%s", roomPath, direction)
+			d, ok := DirectionFromString(direction)
+			if !ok {
+				l.report(LintError, path, doorPath, "unknown direction '%s'", direction)
+				continue
+			}
+			if door.Room == "" {
+				l.report(LintError, path, doorPath+".id", "missing destination room")
+				continue
+			}
+			exit := &lintExit{
+				path:   doorPath + ".id",
+				roomID: area.ID + "_" + door.Room,
+			}
+			if strings.HasPrefix(door.Room, "__EXT__") {
+				exit.roomID = door.Room[7:]
+				exit.external = true
+			}
+			lr.exits[d] = exit
+		}
+	}
+
+	if area.RecallRoom == "" {
+		l.report(LintError, path, "$.recall_room", "area '%s' has no recall room", area.ID)
+	} else if !recallFound {
+		l.report(LintError, path, "$.recall_room", "cannot find recall room '%s' in area '%s'", area.RecallRoom, area.ID)
+	}
+
+	for i, reset := range area.Resets {
+		l.lintReset(path, fmt.Sprintf("$.resets[%d]", i), area.ID, reset)
+	}
+
+	npcIDs := make(map[string]bool)
+	for i, npc := range area.NPCs {
+		npcPath := fmt.Sprintf("$.npcs[%d]", i)
+		if npc.ID == "" {
+			l.report(LintError, path, npcPath+".id", "missing npc id")
+			continue
+		}
+		if npcIDs[npc.ID] {
+			l.report(LintError, path, npcPath+".id", "npc id '%s' duplicated in area '%s'", npc.ID, area.ID)
+			continue
+		}
+		npcIDs[npc.ID] = true
+		l.npcs[area.ID+"_"+npc.ID] = true
+		l.lintNPC(path, npcPath, area.ID, npc)
+	}
+}
+
+// lintNPC checks that an NPC stands in an existing room and its dialogues refer to existing items and rooms
+func (l *assetLinter) lintNPC(path, npcPath, areaID string, npc *NPC) {
+	if lr, ok := l.rooms[areaID+"_"+npc.Room]; !ok || lr.room.AreaID != areaID {
+		l.report(LintError, path, npcPath+".room", "unknown room '%s' in area '%s'", npc.Room, areaID)
+	}
+	if npc.Name == "" {
+		l.report(LintWarning, path, npcPath+".name", "npc '%s' has no name, the id will be shown", npc.ID)
+	}
+	if npc.Description == "" {
+		l.report(LintWarning, path, npcPath+".description", "npc '%s' has no description", npc.ID)
+	}
+	if len(npc.Greeting) == 0 {
+		l.report(LintWarning, path, npcPath+".greeting", "npc '%s' has no greeting", npc.ID)
+	}
+
+	if npc.Shop != nil {
+		l.lintShop(path, npcPath+".shop", npc.Shop)
+	}
+
+	for i, r := range npc.Greeting {
+		l.lintResponse(path, fmt.Sprintf("%s.greeting[%d]", npcPath, i), areaID, r)
+	}
+	for i, t := range npc.Topics {
+		topicPath := fmt.Sprintf("%s.topics[%d]", npcPath, i)
+		if len(t.Keywords) == 0 {
+			l.report(LintError, path, topicPath+".keywords", "topic of npc '%s' has no keywords", npc.ID)
+		}
+		if len(t.Responses) == 0 {
+			l.report(LintWarning, path, topicPath+".responses", "topic of npc '%s' has no responses", npc.ID)
+		}
+		for j, r := range t.Responses {
+			l.lintResponse(path, fmt.Sprintf("%s.responses[%d]", topicPath, j), areaID, r)
+		}
+	}
+}
+
+// lintShop checks that the shop sells existing items that are worth something
+func (l *assetLinter) lintShop(path, shopPath string, shop *Shop) {
+	if shop.SellRate < 0 || shop.BuyRate < 0 {
+		l.report(LintError, path, shopPath, "rates cannot be negative")
+	}
+	if shop.SellRate > 0 && shop.BuyRate > shop.SellRate {
+		l.report(LintWarning, path, shopPath+".buy_rate", "the shop pays more than it charges, buy rate is capped to the sell rate")
+	}
+	if len(shop.Stock) == 0 {
+		l.report(LintWarning, path, shopPath+".stock", "the shop has no stock")
+	}
+	for i, v := range shop.Stock {
+		stockPath := fmt.Sprintf("%s.stock[%d]", shopPath, i)
+		item, ok := l.items[v.ItemID]
+		if !ok {
+			l.report(LintError, path, stockPath+".item_id", "unknown item id '%s'", v.ItemID)
+			continue
+		}
+		if item.Value <= 0 {
+			l.report(LintWarning, path, stockPath+".item_id", "item '%s' has no value, it will be sold for 1 gold", v.ItemID)
+		}
+		if v.Count < 0 {
+			l.report(LintError, path, stockPath+".count", "count cannot be negative")
+		}
+	}
+}
+
+// lintResponse checks the conditions and actions of a line of dialogue
+func (l *assetLinter) lintResponse(path, responsePath, areaID string, r *Response) {
+	for i, c := range r.Conditions {
+		conditionPath := fmt.Sprintf("%s.conditions[%d]", responsePath, i)
+		if !knownConditions[c.Type] {
+			l.report(LintError, path, conditionPath+".type", "unknown condition type '%s'", c.Type)
+			continue
+		}
+		if (c.Type == ConditionHasItem || c.Type == ConditionLacksItem) && l.items[c.ItemID] == nil {
+			l.report(LintError, path, conditionPath+".item_id", "unknown item id '%s'", c.ItemID)
+		}
+		if (c.Type == ConditionFlag || c.Type == ConditionNoFlag) && c.Flag == "" {
+			l.report(LintError, path, conditionPath+".flag", "missing flag")
+		}
+		if strings.HasPrefix(c.Type, "quest_") && l.quests[c.QuestID] == "" {
+			l.report(LintError, path, conditionPath+".quest_id", "unknown quest id '%s'", c.QuestID)
+		}
+	}
+
+	for i, a := range r.Actions {
+		actionPath := fmt.Sprintf("%s.actions[%d]", responsePath, i)
+		if !knownActions[a.Type] {
+			l.report(LintError, path, actionPath+".type", "unknown action type '%s'", a.Type)
+			continue
+		}
+		switch a.Type {
+		case ActionGiveItem, ActionTakeItem:
+			if l.items[a.ItemID] == nil {
+				l.report(LintError, path, actionPath+".item_id", "unknown item id '%s'", a.ItemID)
+			}
+		case ActionTeleport:
+			roomID := areaID + "_" + a.Room
+			if strings.HasPrefix(a.Room, "__EXT__") {
+				roomID = a.Room[7:]
+			}
+			l.teleports = append(l.teleports, &lintTeleport{file: path, path: actionPath + ".room", roomID: roomID})
+		case ActionSetFlag, ActionClearFlag:
+			if a.Flag == "" {
+				l.report(LintError, path, actionPath+".flag", "missing flag")
+			}
+		case ActionStartQuest:
+			if l.quests[a.QuestID] == "" {
+				l.report(LintError, path, actionPath+".quest_id", "unknown quest id '%s'", a.QuestID)
+			}
+		}
+	}
+}
+
+// lintReset checks that a reset rule refers to existing rooms, mobs, items and doors
+func (l *assetLinter) lintReset(path, resetPath, areaID string, reset *JSONReset) {
+	lr, ok := l.rooms[areaID+"_"+reset.Room]
+	if !ok || lr.room.AreaID != areaID {
+		l.report(LintError, path, resetPath+".room", "unknown room '%s' in area '%s'", reset.Room, areaID)
+		return
+	}
+	if reset.Count < 0 || reset.Max < 0 {
+		l.report(LintError, path, resetPath+".count", "count and max cannot be negative")
+	}
+	if reset.Max > 0 && reset.Max < reset.Count {
+		l.report(LintWarning, path, resetPath+".max", "max %d is lower than count %d", reset.Max, reset.Count)
+	}
+
+	if reset.Type == ResetMob || reset.Type == ResetEquip {
+		if _, ok := l.mobs[reset.MobID]; !ok {
+			l.report(LintError, path, resetPath+".mob_id", "unknown mob id '%s'", reset.MobID)
+		}
+	}
+	if reset.Type == ResetItem || reset.Type == ResetEquip || reset.Type == ResetPut {
+		item, ok := l.items[reset.ItemID]
+		if !ok {
+			l.report(LintError, path, resetPath+".item_id", "unknown item id '%s'", reset.ItemID)
+		} else if reset.Type == ResetEquip && item.Equipment == nil {
+			l.report(LintError, path, resetPath+".item_id", "item '%s' cannot be equipped", reset.ItemID)
+		}
+	}
+
+	switch reset.Type {
+	case ResetMob, ResetItem, ResetEquip:
+	case ResetPut:
+		container, ok := l.items[reset.ContainerID]
+		if !ok {
+			l.report(LintError, path, resetPath+".container_id", "unknown item id '%s'", reset.ContainerID)
+		} else if container.Container == nil {
+			l.report(LintError, path, resetPath+".container_id", "item '%s' cannot hold items", reset.ContainerID)
+		}
+	case ResetDoor:
+		door, ok := lr.room.Neighbours[reset.Direction]
+		if !ok {
+			l.report(LintError, path, resetPath+".direction", "room '%s' has no exit '%s'", reset.Room, reset.Direction)
+		} else if !door.IsDoor && !door.IsClosed && !door.IsLocked {
+			l.report(LintError, path, resetPath+".direction", "exit '%s' of room '%s' has no door", reset.Direction, reset.Room)
+		}
+		if _, ok := doorStateNames[reset.State]; !ok {
+			l.report(LintError, path, resetPath+".state", "unknown door state '%s'", reset.State)
+		}
+	default:
+		l.report(LintError, path, resetPath+".type", "unknown reset type '%s'", reset.Type)
+	}
+}
+
+// lintNeighbours checks that every transition leads to an existing room, and that there is a way back
+func (l *assetLinter) lintNeighbours() {
+	for _, id := range l.roomOrder {
+		lr := l.rooms[id]
+		for d := North; d <= Down; d++ {
+			exit, ok := lr.exits[d]
+			if !ok {
+				continue
+			}
+			target, ok := l.rooms[exit.roomID]
+			if !ok {
+				if exit.external {
+					l.report(LintError, lr.file, exit.path, "dangling external link to unknown room '%s'", exit.roomID)
+				} else {
+					l.report(LintError, lr.file, exit.path, "cannot find neighbour '%s'", exit.roomID)
+				}
+				continue
+			}
+			back, ok := target.exits[d.Opposite()]
+			if !ok || back.roomID != id {
+				l.report(LintWarning, lr.file, exit.path, "one-way exit: '%s' has no %s exit back to '%s'", exit.roomID, directionName(d.Opposite()), id)
+			}
+		}
+	}
+}
+
+// lintKeys checks that every container key is an existing item
+func (l *assetLinter) lintKeys() {
+	for _, k := range l.keys {
+		if l.items[k.itemID] == nil {
+			l.report(LintError, k.file, k.path, "unknown item id '%s'", k.itemID)
+		}
+	}
+}
+
+// lintTeleports checks that every teleport leads to an existing room
+func (l *assetLinter) lintTeleports() {
+	for _, t := range l.teleports {
+		if _, ok := l.rooms[t.roomID]; !ok {
+			l.report(LintError, t.file, t.path, "cannot find room '%s' to teleport to", t.roomID)
+		}
+	}
+}
+
+// lintQuestTargets checks that every quest objective refers to existing rooms and NPCs
+func (l *assetLinter) lintQuestTargets() {
+	for _, t := range l.questTargets {
+		if t.npc {
+			if !l.npcs[t.id] {
+				l.report(LintError, t.file, t.path, "cannot find npc '%s'", t.id)
+			}
+			continue
+		}
+		if _, ok := l.rooms[t.id]; !ok {
+			l.report(LintError, t.file, t.path, "cannot find room '%s'", t.id)
+		}
+	}
+}
+
+// lintReachability checks that every room can be reached from the start room
+func (l *assetLinter) lintReachability(startRoom string) {
+	if _, ok := l.rooms[startRoom]; !ok {
+		l.report(LintError, "", "", "start room '%s' does not exist", startRoom)
+		return
+	}
+
+	visited := map[string]bool{startRoom: true}
+	queue := []string{startRoom}
+	for len(queue) > 0 {
+		current := l.rooms[queue[0]]
+		queue = queue[1:]
+		for _, exit := range current.exits {
+			if _, ok := l.rooms[exit.roomID]; !ok || visited[exit.roomID] {
+				continue
+			}
+			visited[exit.roomID] = true
+			queue = append(queue, exit.roomID)
+		}
+	}
+
+	for _, id := range l.roomOrder {
+		if !visited[id] {
+			lr := l.rooms[id]
+			l.report(LintWarning, lr.file, lr.path, "room '%s' cannot be reached from '%s'", id, startRoom)
+		}
+	}
+}
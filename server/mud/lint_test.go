@@ -0,0 +1,84 @@
+package mud
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// copyAssets copies the asset files under src into dst, replacing the files that already exist
+func copyAssets(t *testing.T, src, dst string) {
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return os.MkdirAll(filepath.Join(dst, rel), 0755)
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filepath.Join(dst, rel), content, 0644)
+	})
+	require.NoError(t, err)
+}
+
+func TestLintAssets(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixture  string
+		expected []string
+	}{
+		{"valid", "", []string{}},
+		{"unknown mob id", "unknown_mob", []string{
+			"areas/town.json: $.resets[0].mob_id: error: unknown mob id 'dragon'",
+		}},
+		{"dangling external link", "dangling_external_link", []string{
+			"areas/town.json: $.rooms[0].neighbours.east.id: error: dangling external link to unknown room 'castle_gate'",
+		}},
+		{"one-way exit", "one_way_exit", []string{
+			"areas/town.json: $.rooms[0].neighbours.north.id: warning: one-way exit: 'town_bakery' has no south exit back to 'town_square'",
+		}},
+		{"unreachable room", "unreachable_room", []string{
+			"areas/town.json: $.rooms[2]: warning: room 'town_cellar' cannot be reached from 'town_square'",
+		}},
+		{"unknown direction", "unknown_direction", []string{
+			"areas/town.json: $.rooms[0].neighbours.sideways: error: unknown direction 'sideways'",
+		}},
+		{"duplicate id", "duplicate_id", []string{
+			"areas/town.json: $.rooms[2].id: error: room id 'town_bakery' duplicated, first defined at areas/town.json:$.rooms[1]",
+		}},
+		{"missing description", "missing_description", []string{
+			"areas/town.json: $.rooms[1].short_description: error: room 'town_bakery' has no short description",
+			"areas/town.json: $.rooms[1].long_description: warning: room 'town_bakery' has no long description",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "mudlint")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			copyAssets(t, filepath.Join("testdata", "lint", "base"), dir)
+			if tt.fixture != "" {
+				copyAssets(t, filepath.Join("testdata", "lint", tt.fixture), dir)
+			}
+
+			problems := []string{}
+			for _, p := range LintAssets(dir, "") {
+				problems = append(problems, strings.Replace(p.String(), dir+string(filepath.Separator), "", -1))
+			}
+			assert.Equal(t, tt.expected, problems)
+		})
+	}
+}
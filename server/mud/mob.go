@@ -2,22 +2,47 @@ package mud
 
 import (
 	"fmt"
+	"math/rand"
+	"strings"
 	"time"
 )
 
 const (
 	//MobRegenTime marks how long the routine sleep between regens
 	MobRegenTime = 1 * time.Minute
-	//MobSpawnTime marks how long does it take for a mob to reswpawn
-	MobSpawnTime = 5 * time.Minute
 )
 
+const (
+	// MobFlagAggressive marks mobs that attack the players they can see
+	MobFlagAggressive = "aggressive"
+	// MobFlagWanderer marks mobs that move around the rooms of their own area
+	MobFlagWanderer = "wanderer"
+	// MobFlagSentinel marks mobs that never leave the room they were spawned in
+	MobFlagSentinel = "sentinel"
+	// MobFlagCoward marks mobs that flee from battle when badly hurt
+	MobFlagCoward = "coward"
+	// MobFlagHelper marks mobs that join the battles of other mobs of their kind in the same room
+	MobFlagHelper = "helper"
+	// MobFlagBully marks mobs that always attack the weakest player of the battle instead of the tank
+	MobFlagBully = "bully"
+)
+
+// knownMobFlags lists all the flags that a mob may have
+var knownMobFlags = map[string]bool{
+	MobFlagAggressive: true,
+	MobFlagWanderer:   true,
+	MobFlagSentinel:   true,
+	MobFlagCoward:     true,
+	MobFlagHelper:     true,
+	MobFlagBully:      true,
+}
+
 func (m *Mob) finishMobRoutine() bool {
 	select {
 	case <-worldShutDown:
 		return true
 	default:
-		return false
+		return m.CurrentHP <= 0 || m.despawned
 	}
 }
 
@@ -26,41 +51,75 @@ type MobList []*Mob
 
 // Mob represents one single enemy
 type Mob struct {
-	// ID represents the type of monster. It is also the name shown to the player
-	ID string
+	// ID represents the type of monster. It is always a keyword to target the mob
+	ID string `json:"id"`
+	// Appearance contains how the mob is named and described to the players
+	Appearance
+	// Flags lists the behaviour of the mob: aggressive, wanderer, sentinel, coward, helper or bully
+	Flags []string `json:"flags"`
 	// Stats are the stats of the mob
-	Stats Stats
+	Stats Stats `json:"stats"`
 	// MaxHP denotes the Maximum Health points
-	MaxHP int
+	MaxHP int `json:"max_hp"`
 	// CurrentHP denotes the current Health points
-	CurrentHP int
+	CurrentHP int `json:"-"`
 	// Experience how many experience points the mob provides
-	Experience int
+	Experience int `json:"experience"`
 	// Effects show all the magical effects that the mob is currently under
-	Effects EffectList
+	Effects EffectList `json:"effects"`
 	// Drops contains all the items dropped by the mob
-	Drops []*Drop
+	Drops []*Drop `json:"drops"`
+	// Gold is the most gold coins the mob carries. When killed, it drops between half of it and all of it
+	Gold int `json:"gold"`
 	// DeadAt tells when the monster was defeated
-	DeadAt time.Time
+	DeadAt time.Time `json:"-"`
+	// Equip contains the currently equipped items
+	Equip PlayerEquipment `json:"-"`
+	// CurrentRoom shows on which room the mob is currently on
+	CurrentRoom *Room `json:"-"`
+	// despawned denotes that the mob has been removed from the world without dying
+	despawned bool
 }
 
 // Drop represents a drop from a monster with the probability to drop
 type Drop struct {
-	// Item is the item to drop
-	Item Item
+	// ItemID is the ID of the item to drop
+	ItemID string `json:"item_id"`
 	// Probability is the chance to get the item as x out of 10000
-	Probability int
+	Probability int `json:"probability"`
+	// item is the template of the item to drop
+	item *Item
 }
 
-// Spawn creates a new mob using another mob as template
-func (m *Mob) Spawn() *Mob {
+// Spawn creates a new mob in the room using another mob as template
+func (m *Mob) Spawn(room *Room) *Mob {
 	newMob := *m
 	newMob.Effects = EffectList{}
+	newMob.Equip = PlayerEquipment{}
 	newMob.CurrentHP = newMob.MaxHP
+	newMob.CurrentRoom = room
 	newMob.start()
 	return &newMob
 }
 
+// HasFlag returns whether the mob has certain behaviour flag
+func (m *Mob) HasFlag(flag string) bool {
+	for _, v := range m.Flags {
+		if v == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// Wear equips the item on the mob, replacing anything it had on the same slot
+func (m *Mob) Wear(item *Item) {
+	if item.Equipment == nil {
+		return
+	}
+	m.Equip[item.Equipment.Slot] = item
+}
+
 // Show returns the string of how the user is seen
 func (m *Mob) Show(canSeeHidden, canSeeInvisible bool) string {
 	if (!canSeeHidden && m.IsHidden()) ||
@@ -68,7 +127,25 @@ func (m *Mob) Show(canSeeHidden, canSeeInvisible bool) string {
 		return ""
 	}
 
-	return fmt.Sprintf("A %s is here.", m.ID)
+	return m.Appearance.Show()
+}
+
+// Look returns the description shown when a player looks at the mob
+func (m *Mob) Look() string {
+	lines := []string{}
+	if m.Description != "" {
+		lines = append(lines, m.Description, "")
+	}
+	lines = append(lines, fmt.Sprintf("%s %s.", capitalize(m.DefiniteName()), healthCondition(m.CurrentHP, m.MaxHP)))
+	if equip := m.Equip.Show(); len(equip) > 0 {
+		lines = append(lines, "", capitalize(m.Gender.Subject())+" is using:", "* "+strings.Join(equip, "\n* "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Matches returns whether the player may refer to this mob by keyword
+func (m *Mob) Matches(keyword string) bool {
+	return strings.EqualFold(keyword, m.ID) || m.HasKeyword(keyword)
 }
 
 // IsHidden returns whether the character is hidden
@@ -81,18 +158,37 @@ func (m *Mob) IsInvisible() bool {
 	return m.Effects.GrantInvisible()
 }
 
+// CanSeeHidden returns whether the mob can see hidden things
+func (m *Mob) CanSeeHidden() bool {
+	return m.Effects.CanSeeHidden() || m.Equip.CanSeeHidden()
+}
+
+// CanSeeInvisible returns whether the mob can see invisible things
+func (m *Mob) CanSeeInvisible() bool {
+	return m.Effects.CanSeeInvisible() || m.Equip.CanSeeInvisible()
+}
+
+// CanSeePlayer returns whether the player is visible to this mob. Mobs never notice ghosts
+func (m *Mob) CanSeePlayer(p *Player) bool {
+	return !p.IsGhost &&
+		(!p.IsHidden() || m.CanSeeHidden()) &&
+		(!p.IsInvisible() || m.CanSeeInvisible())
+}
+
 // GetAttack returns the attack of the mob
 func (m *Mob) GetAttack() int {
 	str := m.GetCurrentStat(Strength)
+	attEquipModifiers := m.Equip.GetLeftAttack() + m.Equip.GetRightAttack() + m.Equip.GetAttackModifiers()
 	attEffectModifiers := m.Effects.GetAttackModifiers()
-	return min(0, str+attEffectModifiers)
+	return max(0, str+attEquipModifiers+attEffectModifiers)
 }
 
-// GetCurrentStat returns the current stat of the mob
+// GetCurrentStat returns the current stat of the mob: the base stat plus the equipment and the effects, kept between MinStat and MaxStat
 func (m *Mob) GetCurrentStat(s Stat) int {
 	base := m.Stats[s]
+	equipModifiers := m.Equip.GetStatModifiers(s)
 	effectModifiers := m.Effects.GetStatModifiers(s)
-	return min(0, base+effectModifiers)
+	return clampStat(base + equipModifiers + effectModifiers)
 }
 
 // GetCurrentDefense returns the current defense of the mob
@@ -100,9 +196,45 @@ func (m *Mob) GetCurrentDefense() int {
 	return m.GetCurrentStat(Constitution)
 }
 
-// Dead kills the mob
+// Dead kills the mob, removing it from the room and leaving its drops on the ground, rolling the ones with random affixes.
+// The area reset will spawn a new one.
 func (m *Mob) Dead() {
 	m.DeadAt = time.Now()
+	if m.CurrentRoom == nil {
+		return
+	}
+	m.CurrentRoom.RemoveMob(m)
+
+	dropped := ItemList{}
+	for _, d := range m.Drops {
+		if d.item == nil || rand.Intn(10000) >= d.Probability {
+			continue
+		}
+		dropped = append(dropped, d.item.Roll())
+	}
+	for _, item := range m.Equip {
+		dropped = append(dropped, item)
+	}
+	m.Equip = PlayerEquipment{}
+
+	corpse := newCorpse(m.IndefiniteName(), m.Keywords, dropped)
+	m.CurrentRoom.Items = append(m.CurrentRoom.Items, corpse)
+	if len(dropped) > 0 {
+		m.CurrentRoom.Announce(fmt.Sprintf("You see %s on %s.", strings.Join(dropped.Names(), ", "), corpse.Name))
+	}
+}
+
+// DropGold returns how many gold coins the mob drops when killed
+func (m *Mob) DropGold() int {
+	if m.Gold <= 0 {
+		return 0
+	}
+	return m.Gold/2 + rand.Intn(m.Gold-m.Gold/2+1)
+}
+
+// Despawn removes the mob from the world without dropping anything, like when its area is reloaded
+func (m *Mob) Despawn() {
+	m.despawned = true
 }
 
 // start runs the mob routine
@@ -112,14 +244,8 @@ func (m *Mob) start() {
 			if m.finishMobRoutine() {
 				return
 			}
-			if m.CurrentHP <= 0 && time.Now().Unix() > m.DeadAt.Add(MobSpawnTime).Unix() {
-				m.CurrentHP = m.MaxHP
-			}
-
-			if m.CurrentHP > 0 {
-				toRegen := max(1, int(float64(m.MaxHP)*0.1))
-				m.CurrentHP = min(m.MaxHP, m.CurrentHP+toRegen)
-			}
+			toRegen := max(1, int(float64(m.MaxHP)*0.1))
+			m.CurrentHP = min(m.MaxHP, m.CurrentHP+toRegen)
 			time.Sleep(MobRegenTime)
 		}
 	}()
@@ -0,0 +1,163 @@
+package mud
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+const (
+	// MobActTime defines how long the mobs wait between actions
+	MobActTime = 10 * time.Second
+	// MobWanderProbability is the chance of a wanderer to move on each action, as x out of 100
+	MobWanderProbability = 25
+	// MobFleeHPPercent is the percentage of health points under which cowards flee from battle
+	MobFleeHPPercent = 25
+)
+
+func finishMobsAct() bool {
+	select {
+	case <-worldShutDown:
+		return true
+	default:
+		return false
+	}
+}
+
+// mobsAct periodically runs the behaviour of all the mobs in the world
+func (w *World) mobsAct() {
+	for {
+		time.Sleep(MobActTime)
+		if finishMobsAct() {
+			return
+		}
+
+		acted := make(map[*Mob]bool)
+		for _, room := range w.rooms {
+			for _, m := range append(MobList{}, room.Mobs...) {
+				if acted[m] {
+					continue
+				}
+				acted[m] = true
+				w.mobAct(m)
+			}
+		}
+	}
+}
+
+// mobAct runs the behaviour of a single mob that is not fighting
+func (w *World) mobAct(m *Mob) {
+	if m.CurrentHP <= 0 || m.despawned {
+		return
+	}
+	if b := w.GetMobBattle(m); b != nil && !b.finishBattle() {
+		return
+	}
+
+	if m.HasFlag(MobFlagHelper) && w.helpKin(m) {
+		return
+	}
+
+	if m.HasFlag(MobFlagAggressive) && m.Aggress() {
+		return
+	}
+
+	if !m.HasFlag(MobFlagWanderer) || m.HasFlag(MobFlagSentinel) || rand.Intn(100) >= MobWanderProbability {
+		return
+	}
+	d, ok := m.randomExit()
+	if !ok {
+		return
+	}
+	m.Move(d, fmt.Sprintf("%s leaves %s.", capitalize(m.DefiniteName()), directionName(d)))
+	if m.HasFlag(MobFlagAggressive) {
+		m.Aggress()
+	}
+}
+
+// helpKin makes the mob join a battle where a mob of its kind in the same room is fighting. Returns whether it joined any.
+func (w *World) helpKin(m *Mob) bool {
+	for _, other := range m.CurrentRoom.Mobs {
+		if other == m || other.ID != m.ID {
+			continue
+		}
+		b := w.GetMobBattle(other)
+		if b == nil || b.finishBattle() {
+			continue
+		}
+		b.AddMob(m)
+		b.NotifyAll(fmt.Sprintf("%s joins the fight!", capitalize(m.DefiniteName())))
+		return true
+	}
+	return false
+}
+
+// Aggress makes the mob attack a player it can see in the room, preferring the ones caught asleep. Returns whether it attacked anyone.
+func (m *Mob) Aggress() bool {
+	var victim *Player
+	for _, p := range m.CurrentRoom.Players {
+		if !m.CanSeePlayer(p) {
+			continue
+		}
+		if victim == nil || (p.IsSleeping() && !victim.IsSleeping()) {
+			victim = p
+		}
+	}
+	if victim == nil {
+		return false
+	}
+	m.Attack(victim)
+	return true
+}
+
+// Attack starts a battle between the mob and the player
+func (m *Mob) Attack(p *Player) {
+	p.catchAsleep()
+	p.Notify(fmt.Sprintf("%s attacks you!", capitalize(m.DefiniteName())))
+	m.CurrentRoom.Act(p, fmt.Sprintf("%s attacks %s!", capitalize(m.DefiniteName()), p.Name))
+	p.CreateBattle(m)
+}
+
+// Move moves the mob to the room in direction d, telling the players in the room it leaves with the message
+func (m *Mob) Move(d Direction, message string) {
+	from := m.CurrentRoom
+	to := from.GetNeighbourRoom(d)
+	from.RemoveMob(m)
+	from.ActMob(m, message)
+	m.CurrentRoom = to
+	to.Mobs = append(to.Mobs, m)
+	to.ActMob(m, fmt.Sprintf("%s has arrived.", capitalize(m.IndefiniteName())))
+}
+
+// Flee moves the mob out of the room if it can. Sentinels never flee. Returns whether the mob fled.
+func (m *Mob) Flee() bool {
+	if m.HasFlag(MobFlagSentinel) {
+		return false
+	}
+	d, ok := m.randomExit()
+	if !ok {
+		return false
+	}
+	m.Move(d, fmt.Sprintf("%s flees %s!", capitalize(m.DefiniteName()), directionName(d)))
+	return true
+}
+
+// ShouldFlee returns whether the mob is a coward hurt enough to run away
+func (m *Mob) ShouldFlee() bool {
+	return m.HasFlag(MobFlagCoward) && m.CurrentHP > 0 && m.CurrentHP*100 < m.MaxHP*MobFleeHPPercent
+}
+
+// randomExit returns a random open direction leading to a room of the same area
+func (m *Mob) randomExit() (Direction, bool) {
+	exits := []Direction{}
+	for d, door := range m.CurrentRoom.Neighbours {
+		if door.room.AreaID != m.CurrentRoom.AreaID || !m.CurrentRoom.CanMove(d, m.CanSeeHidden(), m.CanSeeInvisible()) {
+			continue
+		}
+		exits = append(exits, d)
+	}
+	if len(exits) == 0 {
+		return North, false
+	}
+	return exits[rand.Intn(len(exits))], true
+}
@@ -1 +1,418 @@
 package mud
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// ConditionMinLevel holds when the player level is at least Level
+	ConditionMinLevel = "min_level"
+	// ConditionMaxLevel holds when the player level is at most Level
+	ConditionMaxLevel = "max_level"
+	// ConditionHasItem holds when the player carries the item ItemID
+	ConditionHasItem = "has_item"
+	// ConditionLacksItem holds when the player does not carry the item ItemID
+	ConditionLacksItem = "lacks_item"
+	// ConditionFlag holds when the player has the story flag Flag
+	ConditionFlag = "flag"
+	// ConditionNoFlag holds when the player does not have the story flag Flag
+	ConditionNoFlag = "no_flag"
+	// ConditionQuestNotStarted holds when the player never started or abandoned the quest QuestID
+	ConditionQuestNotStarted = "quest_not_started"
+	// ConditionQuestActive holds when the player is working on the quest QuestID
+	ConditionQuestActive = "quest_active"
+	// ConditionQuestCompleted holds when the player completed the quest QuestID
+	ConditionQuestCompleted = "quest_completed"
+	// ConditionGhost holds when the player is a ghost waiting to be resurrected
+	ConditionGhost = "ghost"
+)
+
+// knownConditions lists all the conditions a response may have
+var knownConditions = map[string]bool{
+	ConditionMinLevel:        true,
+	ConditionMaxLevel:        true,
+	ConditionHasItem:         true,
+	ConditionLacksItem:       true,
+	ConditionFlag:            true,
+	ConditionNoFlag:          true,
+	ConditionQuestNotStarted: true,
+	ConditionQuestActive:     true,
+	ConditionQuestCompleted:  true,
+	ConditionGhost:           true,
+}
+
+const (
+	// ActionGiveItem gives a new ItemID to the player
+	ActionGiveItem = "give_item"
+	// ActionTakeItem takes the item ItemID from the player inventory
+	ActionTakeItem = "take_item"
+	// ActionTeleport sends the player to Room
+	ActionTeleport = "teleport"
+	// ActionSetFlag sets the story flag Flag on the player
+	ActionSetFlag = "set_flag"
+	// ActionClearFlag removes the story flag Flag from the player
+	ActionClearFlag = "clear_flag"
+	// ActionStartQuest gives the quest QuestID to the player
+	ActionStartQuest = "start_quest"
+	// ActionResurrect brings the player back to life, if the player is a ghost
+	ActionResurrect = "resurrect"
+)
+
+// knownActions lists all the actions a response may have
+var knownActions = map[string]bool{
+	ActionGiveItem:   true,
+	ActionTakeItem:   true,
+	ActionTeleport:   true,
+	ActionSetFlag:    true,
+	ActionClearFlag:  true,
+	ActionStartQuest: true,
+	ActionResurrect:  true,
+}
+
+// NPC is a non-hostile character that players can talk to
+type NPC struct {
+	// ID is the unique identifier of the NPC inside the area. It is always a keyword to refer to the NPC
+	ID string `json:"id"`
+	// Room is the ID of the room inside the area where the NPC stands
+	Room string `json:"room"`
+	// Appearance contains how the NPC is named and described to the players
+	Appearance
+	// Greeting lists what the NPC may say when a player talks to it. The first one whose conditions hold is used
+	Greeting []*Response `json:"greeting"`
+	// Topics lists what players can ask the NPC about
+	Topics []*Topic `json:"topics"`
+	// Shop contains the stock and prices of the NPC if it is a shopkeeper
+	Shop *Shop `json:"shop,omitempty"`
+	// CurrentRoom is the room where the NPC stands
+	CurrentRoom *Room `json:"-"`
+	// areaID is the ID of the area the NPC belongs to
+	areaID string
+}
+
+// Topic is something players can ask an NPC about
+type Topic struct {
+	// Keywords are the words that trigger the topic. Example: forest
+	Keywords []string `json:"keywords"`
+	// Responses lists what the NPC may answer. The first one whose conditions hold is used
+	Responses []*Response `json:"responses"`
+}
+
+// Response is a line of dialogue, along with the conditions for the NPC to say it and what happens afterwards
+type Response struct {
+	// Conditions must all hold for the response to be used
+	Conditions []*Condition `json:"conditions"`
+	// Say is what the NPC says
+	Say string `json:"say"`
+	// Actions are run, in order, after the NPC speaks
+	Actions []*DialogueAction `json:"actions"`
+}
+
+// Condition is a requirement on the player for a response to be used
+type Condition struct {
+	// Type is the kind of condition: min_level, max_level, has_item, lacks_item, flag, no_flag,
+	// quest_not_started, quest_active, quest_completed or ghost
+	Type string `json:"type"`
+	// Level is the level to compare with (min_level, max_level)
+	Level int `json:"level"`
+	// ItemID is the item to look for in the player inventory (has_item, lacks_item)
+	ItemID string `json:"item_id"`
+	// Flag is the story flag to look for (flag, no_flag)
+	Flag string `json:"flag"`
+	// QuestID is the quest whose state is checked (quest_not_started, quest_active, quest_completed)
+	QuestID string `json:"quest_id"`
+}
+
+// DialogueAction is something that happens to the player after a response
+type DialogueAction struct {
+	// Type is the kind of action: give_item, take_item, teleport, set_flag, clear_flag, start_quest or resurrect
+	Type string `json:"type"`
+	// ItemID is the item to give or take (give_item, take_item)
+	ItemID string `json:"item_id"`
+	// Room is the ID of the room inside the area to send the player to. Rooms on other areas have the __EXT__ prefix (teleport)
+	Room string `json:"room"`
+	// Flag is the story flag to set or remove (set_flag, clear_flag)
+	Flag string `json:"flag"`
+	// QuestID is the quest to give (start_quest)
+	QuestID string `json:"quest_id"`
+	// item is the template of the item to give
+	item *Item
+	// room is the room to teleport to
+	room *Room
+	// quest is the quest to give
+	quest *Quest
+}
+
+// Matches returns whether the player may refer to this NPC by keyword
+func (n *NPC) Matches(keyword string) bool {
+	return strings.EqualFold(keyword, n.ID) || n.HasKeyword(keyword)
+}
+
+// FullID returns the ID of the NPC prefixed by the ID of its area, unique in the world. Example: midgaard_captain
+func (n *NPC) FullID() string {
+	return n.areaID + "_" + n.ID
+}
+
+// Look returns the description shown when a player looks at the NPC
+func (n *NPC) Look() string {
+	if n.Description == "" {
+		return fmt.Sprintf("You see nothing special about %s.", n.DefiniteName())
+	}
+	return n.Description
+}
+
+// GetTopic returns the topic triggered by the subject, or nil if the NPC knows nothing about it.
+// The whole subject is tried first, and then each of its words.
+func (n *NPC) GetTopic(subject string) *Topic {
+	candidates := append([]string{subject}, strings.Fields(subject)...)
+	for _, candidate := range candidates {
+		for _, t := range n.Topics {
+			for _, k := range t.Keywords {
+				if strings.EqualFold(candidate, k) {
+					return t
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Talk makes the NPC greet the player
+func (n *NPC) Talk(p *Player) {
+	r := chooseResponse(n.Greeting, p)
+	if r == nil {
+		p.Notify(fmt.Sprintf("%s does not seem interested in talking to you.", capitalize(n.DefiniteName())))
+		return
+	}
+	n.respond(r, p)
+}
+
+// Ask makes the NPC answer the player about the subject
+func (n *NPC) Ask(p *Player, subject string) {
+	var r *Response
+	if t := n.GetTopic(subject); t != nil {
+		r = chooseResponse(t.Responses, p)
+	}
+	if r == nil {
+		p.Notify(fmt.Sprintf("%s shrugs. %s does not know anything about %s.", capitalize(n.DefiniteName()), capitalize(n.Gender.Subject()), subject))
+		return
+	}
+	n.respond(r, p)
+}
+
+// respond says the response to the player and runs its actions
+func (n *NPC) respond(r *Response, p *Player) {
+	if r.Say != "" {
+		p.Notify(fmt.Sprintf("%s says: %s", capitalize(n.DefiniteName()), r.Say))
+	}
+	for _, a := range r.Actions {
+		a.apply(n, p)
+	}
+}
+
+// responses returns all the responses of the NPC, both greetings and answers to topics
+func (n *NPC) responses() []*Response {
+	responses := append([]*Response{}, n.Greeting...)
+	for _, t := range n.Topics {
+		responses = append(responses, t.Responses...)
+	}
+	return responses
+}
+
+// chooseResponse returns the first response whose conditions hold for the player, or nil if none does
+func chooseResponse(responses []*Response, p *Player) *Response {
+OUTER:
+	for _, r := range responses {
+		for _, c := range r.Conditions {
+			if !c.Holds(p) {
+				continue OUTER
+			}
+		}
+		return r
+	}
+	return nil
+}
+
+// Holds returns whether the condition holds for the player
+func (c *Condition) Holds(p *Player) bool {
+	switch c.Type {
+	case ConditionMinLevel:
+		return p.Level >= c.Level
+	case ConditionMaxLevel:
+		return p.Level <= c.Level
+	case ConditionHasItem:
+		return p.Inventory.Count(c.ItemID) > 0
+	case ConditionLacksItem:
+		return p.Inventory.Count(c.ItemID) == 0
+	case ConditionFlag:
+		return p.HasFlag(c.Flag)
+	case ConditionNoFlag:
+		return !p.HasFlag(c.Flag)
+	case ConditionQuestNotStarted:
+		return p.QuestState(c.QuestID) == QuestNotStarted
+	case ConditionQuestActive:
+		return p.QuestState(c.QuestID) == QuestActive
+	case ConditionQuestCompleted:
+		return p.QuestState(c.QuestID) == QuestCompleted
+	case ConditionGhost:
+		return p.IsGhost
+	default:
+		return false
+	}
+}
+
+// apply runs the action on the player
+func (a *DialogueAction) apply(n *NPC, p *Player) {
+	switch a.Type {
+	case ActionGiveItem:
+		item := a.item.Spawn()
+		p.Inventory = append(p.Inventory, item)
+		p.Notify(fmt.Sprintf("%s gives you %s.", capitalize(n.DefiniteName()), item.Name))
+	case ActionTakeItem:
+		for _, item := range p.Inventory {
+			if item.ID == a.ItemID {
+				p.Inventory = p.Inventory.Remove(item)
+				p.Notify(fmt.Sprintf("You give %s to %s.", item.Name, n.DefiniteName()))
+				break
+			}
+		}
+	case ActionTeleport:
+		if a.room != nil {
+			p.Teleport(a.room)
+		}
+	case ActionSetFlag:
+		p.SetFlag(a.Flag, true)
+	case ActionClearFlag:
+		p.SetFlag(a.Flag, false)
+	case ActionStartQuest:
+		if a.quest != nil {
+			p.StartQuest(a.quest)
+		}
+	case ActionResurrect:
+		if p.IsGhost {
+			p.Resurrect(n.DefiniteName())
+		}
+	}
+}
+
+// resolveNPC checks the NPC of an area and links it to the rooms, items and quests of the world
+func resolveNPC(n *NPC, areaID string, rooms map[string]*Room, items map[string]*Item, quests map[string]*Quest) error {
+	n.areaID = areaID
+	room, ok := rooms[areaID+"_"+n.Room]
+	if !ok {
+		return fmt.Errorf("cannot find room %s for npc %s on area %s", n.Room, n.ID, areaID)
+	}
+	n.CurrentRoom = room
+	if n.Name == "" {
+		n.Name = n.ID
+	}
+	if n.Shop != nil {
+		if err := n.Shop.resolve(n.ID, areaID, items); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range n.Topics {
+		if len(t.Keywords) == 0 {
+			return fmt.Errorf("topic without keywords for npc %s on area %s", n.ID, areaID)
+		}
+	}
+
+	for _, r := range n.responses() {
+		for _, c := range r.Conditions {
+			if !knownConditions[c.Type] {
+				return fmt.Errorf("unknown condition %s for npc %s on area %s", c.Type, n.ID, areaID)
+			}
+			if strings.HasPrefix(c.Type, "quest_") {
+				if _, ok = quests[c.QuestID]; !ok {
+					return fmt.Errorf("cannot find quest with id %s for npc %s on area %s", c.QuestID, n.ID, areaID)
+				}
+			}
+		}
+		for _, a := range r.Actions {
+			switch a.Type {
+			case ActionGiveItem, ActionTakeItem:
+				if a.item, ok = items[a.ItemID]; !ok {
+					return fmt.Errorf("cannot find item with id %s for npc %s on area %s", a.ItemID, n.ID, areaID)
+				}
+			case ActionTeleport:
+				roomID := areaID + "_" + a.Room
+				if strings.HasPrefix(a.Room, "__EXT__") {
+					roomID = a.Room[7:]
+				}
+				if a.room, ok = rooms[roomID]; !ok {
+					return fmt.Errorf("cannot find room %s to teleport for npc %s on area %s", a.Room, n.ID, areaID)
+				}
+			case ActionStartQuest:
+				if a.quest, ok = quests[a.QuestID]; !ok {
+					return fmt.Errorf("cannot find quest with id %s for npc %s on area %s", a.QuestID, n.ID, areaID)
+				}
+			case ActionSetFlag, ActionClearFlag, ActionResurrect:
+			default:
+				return fmt.Errorf("unknown action %s for npc %s on area %s", a.Type, n.ID, areaID)
+			}
+		}
+	}
+	return nil
+}
+
+// relink points the teleports towards the replaced areas to the new rooms, or to nowhere if the room does not exist anymore
+func (n *NPC) relink(rooms map[string]*Room, replaced map[string]*Area) {
+	for _, r := range n.responses() {
+		for _, a := range r.Actions {
+			if a.room == nil {
+				continue
+			}
+			if _, ok := replaced[a.room.AreaID]; !ok {
+				continue
+			}
+			newRoom, ok := rooms[a.room.ID]
+			if !ok || newRoom.AreaID != a.room.AreaID {
+				newRoom = nil
+			}
+			a.room = newRoom
+		}
+	}
+}
+
+// linkTemplates returns how to point the shop and the actions of the NPC to the templates with the same ID.
+// Fails if any of them, or any quest checked by the conditions, does not exist anymore
+func (n *NPC) linkTemplates(t *templates) ([]func(), error) {
+	links := []func(){}
+	if n.Shop != nil {
+		for _, v := range n.Shop.Stock {
+			v := v
+			item, ok := t.items[v.ItemID]
+			if !ok {
+				return nil, fmt.Errorf("cannot find item with id %s for the shop of npc %s on area %s", v.ItemID, n.ID, n.areaID)
+			}
+			links = append(links, func() { v.item = item })
+		}
+	}
+
+	for _, r := range n.responses() {
+		for _, c := range r.Conditions {
+			if _, ok := t.quests[c.QuestID]; strings.HasPrefix(c.Type, "quest_") && !ok {
+				return nil, fmt.Errorf("cannot find quest with id %s for npc %s on area %s", c.QuestID, n.ID, n.areaID)
+			}
+		}
+		for _, a := range r.Actions {
+			a := a
+			switch a.Type {
+			case ActionGiveItem, ActionTakeItem:
+				item, ok := t.items[a.ItemID]
+				if !ok {
+					return nil, fmt.Errorf("cannot find item with id %s for npc %s on area %s", a.ItemID, n.ID, n.areaID)
+				}
+				links = append(links, func() { a.item = item })
+			case ActionStartQuest:
+				quest, ok := t.quests[a.QuestID]
+				if !ok {
+					return nil, fmt.Errorf("cannot find quest with id %s for npc %s on area %s", a.QuestID, n.ID, n.areaID)
+				}
+				links = append(links, func() { a.quest = quest })
+			}
+		}
+	}
+	return links, nil
+}
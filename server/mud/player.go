@@ -10,6 +10,8 @@ import (
 const (
 	//PlayerRegenTime marks how long the routine sleep between regens
 	PlayerRegenTime = 1 * time.Minute
+	// StartingGold is how much gold new players carry
+	StartingGold = 10
 )
 
 // Player represents one single player
@@ -21,17 +23,17 @@ type Player struct {
 	// Stats are the base stats of the character
 	Stats Stats
 	// Class is the character class
-	Class PlayerClass
+	Class *Class
 	// Race is the character race
-	Race Race
+	Race *Race
 	// Level is the current experience leve
 	Level int
 	// Experience how many experience points the player has. It is used for levelling up
 	Experience int
-	// IsSleeping shows whether the player is sleeping and should receive messages from the bot or not
-	IsSleeping bool
+	// Position is the posture of the player: standing, sitting, resting, sleeping, fighting or stunned
+	Position Position
 	// Inventory contains all the items carried by the character
-	Inventory []*Item
+	Inventory ItemList
 	// Equip contains the currently equipped items
 	Equip PlayerEquipment
 	// Effects show all the magical effects that the character is currently under
@@ -44,12 +46,40 @@ type Player struct {
 	Notify func(message string)
 	// CreateBattle creates a battle with a mob
 	CreateBattle func(mob *Mob)
+	// AttackPlayer creates a battle with another player
+	AttackPlayer func(rival *Player)
+	// GetDeathPenalties returns the consequences of dying set by the administrators
+	GetDeathPenalties func() DeathPenalties
 	// MaxHP denotes the Maximum Health points
 	MaxHP int
 	// CurrentHP denotes the current Health points
 	CurrentHP int
-	// IsFighting denotes whether the player is fighting
-	IsFighting bool
+	// CurrentMana denotes the current mana, spent on casting spells
+	CurrentMana int
+	// CurrentMoves denotes the current movement points, spent on walking from room to room
+	CurrentMoves int
+	// Prompt denotes whether the health, mana and movement points are shown after every notification
+	Prompt bool
+	// Gold is how much money the player carries
+	Gold int
+	// Flags are the story flags set on the player by the dialogues with NPCs
+	Flags map[string]bool
+	// Quests contains the progress on the quests the player is working on or completed, keyed by quest ID
+	Quests map[string]*PlayerQuest
+	// PvP denotes whether the player accepts fighting other players outside arenas
+	PvP bool
+	// IsGhost denotes whether the player died and is waiting to be brought back to life
+	IsGhost bool
+	// Deaths is the log of the last deaths of the player
+	Deaths []*DeathRecord
+	// queued is the item the player consumes on the next turn of the battle instead of attacking
+	queued *Item
+	// lastMove is when the player last moved to another room
+	lastMove time.Time
+	// following is the player this player moves along with
+	following *Player
+	// group is the group the player is a member of, if any
+	group *Group
 }
 
 func (p *Player) finishPlayerRoutine() bool {
@@ -69,8 +99,8 @@ func (p *Player) GetLeftAttack() int {
 	}
 	str := p.GetCurrentStat(Strength)
 	attEquipModifiers := p.Equip.GetAttackModifiers()
-	attEffectModifiers := p.Effects.GetAttackModifiers()
-	return min(0, str+baseAtt+attEquipModifiers+attEffectModifiers)
+	attEffectModifiers := p.effects().GetAttackModifiers()
+	return max(0, str+baseAtt+attEquipModifiers+attEffectModifiers)
 }
 
 // GetRightAttack returns the attack with the weapon on the right hand
@@ -81,16 +111,18 @@ func (p *Player) GetRightAttack() int {
 	}
 	str := p.GetCurrentStat(Strength)
 	attEquipModifiers := p.Equip.GetAttackModifiers()
-	attEffectModifiers := p.Effects.GetAttackModifiers()
-	return min(0, str+baseAtt+attEquipModifiers+attEffectModifiers)
+	attEffectModifiers := p.effects().GetAttackModifiers()
+	return max(0, str+baseAtt+attEquipModifiers+attEffectModifiers)
 }
 
-// GetCurrentStat returns the current stat of the character
+// GetCurrentStat returns the current stat of the character: the base stat plus the race and class bonuses,
+// the equipment and the effects, kept between MinStat and MaxStat
 func (p *Player) GetCurrentStat(s Stat) int {
 	base := p.Stats[s]
+	bonuses := p.traitsStatModifiers(s)
 	equipModifiers := p.Equip.GetStatModifiers(s)
-	effectModifiers := p.Effects.GetStatModifiers(s)
-	return min(0, base+equipModifiers+effectModifiers)
+	effectModifiers := p.effects().GetStatModifiers(s)
+	return clampStat(base + bonuses + equipModifiers + effectModifiers)
 }
 
 // GetCurrentDefense returns the current defense of the character
@@ -100,59 +132,143 @@ func (p *Player) GetCurrentDefense() int {
 
 // CanSeeHidden returns whether the character can see hidden objects
 func (p *Player) CanSeeHidden() bool {
-	return p.Equip.CanSeeHidden() || p.Effects.CanSeeHidden()
+	return p.Equip.CanSeeHidden() || p.effects().CanSeeHidden()
 }
 
 // CanSeeInvisible returns whether the character can see invisible objects
 func (p *Player) CanSeeInvisible() bool {
-	return p.Equip.CanSeeInvisible() || p.Effects.CanSeeInvisible()
+	return p.Equip.CanSeeInvisible() || p.effects().CanSeeInvisible()
 }
 
 // IsHidden returns whether the character is hidden
 func (p *Player) IsHidden() bool {
-	return p.Equip.GrantHidden() || p.Effects.GrantHidden()
+	return p.Equip.GrantHidden() || p.effects().GrantHidden()
 }
 
 // IsInvisible returns whether the character is invisible
 func (p *Player) IsInvisible() bool {
-	return p.Equip.GrantInvisible() || p.Effects.GrantInvisible()
+	return p.Equip.GrantInvisible() || p.effects().GrantInvisible()
 }
 
 // Move moves a character in certain direction, and returns the message to show to the player
 func (p *Player) Move(d Direction) {
-	if p.IsSleeping {
+	if p.IsSleeping() {
 		p.Notify("You cannot sleepwalk.")
 		return
 	}
 
-	if p.IsFighting {
+	if p.IsFighting() {
 		p.Notify("You cannot leave the room while fighting!")
 		return
 	}
 
+	if p.notStanding() {
+		return
+	}
+
 	if !p.CurrentRoom.CanMove(d, p.CanSeeHidden(), p.CanSeeInvisible()) {
 		if p.CanSeeDoor(d) {
-			p.Notify("The door is locked.")
+			if p.CurrentRoom.GetDoor(d).State() == DoorLocked {
+				p.Notify("The door is locked.")
+				return
+			}
+			p.Notify("The door is closed.")
 			return
 		}
 		p.Notify("You cannot go in that direction.")
 		return
 	}
 
+	if p.tooTired() || p.tooExhausted(p.CurrentRoom.GetNeighbourRoom(d)) {
+		return
+	}
+
+	followers := p.followers()
+	p.lastMove = time.Now()
+	p.CurrentMoves -= p.moveCost(p.CurrentRoom.GetNeighbourRoom(d))
 	p.CurrentRoom.Exit(p, d)
 	p.CurrentRoom = p.CurrentRoom.GetNeighbourRoom(d)
+	p.ShowRoom()
 	p.CurrentRoom.Enter(p, d)
+	p.reachTemple()
+	p.QuestEvent(ObjectiveReach, p.CurrentRoom.ID)
+
+	for _, f := range followers {
+		if !f.IsAvailable() {
+			continue
+		}
+		f.Notify(fmt.Sprintf("You follow %s.", p.Name))
+		f.Move(d)
+	}
+}
+
+// Teleport moves the player to the room without going through any door
+func (p *Player) Teleport(room *Room) {
+	p.CurrentRoom.Act(p, fmt.Sprintf("%s disappears in a flash of light.", p.Name))
+	delete(p.CurrentRoom.Players, p.UserID)
+	p.CurrentRoom = room
 	p.ShowRoom()
+	p.CurrentRoom.Act(p, fmt.Sprintf("%s appears in a flash of light.", p.Name))
+	p.CurrentRoom.Players[p.UserID] = p
+	p.reachTemple()
+	p.QuestEvent(ObjectiveReach, p.CurrentRoom.ID)
+}
+
+// recallRoom returns the room the player is sent to when recalling: the recall room of the current area, or the
+// default room if the area has none
+func (p *Player) recallRoom() *Room {
+	if a := p.CurrentRoom.area; a != nil && a.RecallRoom != nil {
+		return a.RecallRoom
+	}
+	return p.DefaultRoom
+}
+
+// ExperienceForLevel returns how many experience points are needed to reach the level
+func ExperienceForLevel(level int) int {
+	return 100 * level * level
+}
+
+// GainExperience gives experience points to the player, levelling up if enough are gathered
+func (p *Player) GainExperience(experience int) {
+	if experience <= 0 {
+		return
+	}
+	p.Experience += experience
+	for p.Experience >= ExperienceForLevel(p.Level+1) {
+		p.Level++
+		p.MaxHP += p.hpPerLevel()
+		p.CurrentHP = p.MaxHP
+		p.CurrentMana = p.MaxMana()
+		p.CurrentMoves = p.MaxMoves()
+		p.Notify(fmt.Sprintf("You are now level %d!", p.Level))
+	}
 }
 
-// CanSeeDoor checks whether a locked door can be seen in certain direction
+// HasFlag returns whether the player has certain story flag
+func (p *Player) HasFlag(flag string) bool {
+	return p.Flags[flag]
+}
+
+// SetFlag sets or removes a story flag on the player
+func (p *Player) SetFlag(flag string, value bool) {
+	if !value {
+		delete(p.Flags, flag)
+		return
+	}
+	if p.Flags == nil {
+		p.Flags = make(map[string]bool)
+	}
+	p.Flags[flag] = true
+}
+
+// CanSeeDoor checks whether a door can be seen in certain direction
 func (p *Player) CanSeeDoor(d Direction) bool {
 	return p.CurrentRoom.CanSeeDoor(d, p.CanSeeHidden(), p.CanSeeInvisible())
 }
 
 // LookRoom returns the current room long description
 func (p *Player) LookRoom() {
-	if p.IsSleeping {
+	if p.IsSleeping() {
 		p.Notify("No matter how hard you look, you see nothing while asleep.")
 		return
 	}
@@ -161,7 +277,7 @@ func (p *Player) LookRoom() {
 
 // ShowRoom returns the string for the current room
 func (p *Player) ShowRoom() {
-	if p.IsSleeping {
+	if p.IsSleeping() {
 		p.Notify("You cannot see much while sleeping.")
 		return
 	}
@@ -175,64 +291,65 @@ func (p *Player) Show(canSeeHidden, canSeeInvisible bool) string {
 		return ""
 	}
 
-	if p.IsSleeping {
+	if p.IsGhost {
+		return fmt.Sprintf("The ghost of %s floats here.", p.Name)
+	}
+
+	switch p.Position {
+	case Sitting:
+		return fmt.Sprintf("%s is sitting here.", p.Name)
+	case Resting:
+		return fmt.Sprintf("%s is resting here.", p.Name)
+	case Sleeping:
 		return fmt.Sprintf("%s is sleeping here.", p.Name)
+	case Fighting, Stunned:
+		return fmt.Sprintf("%s is fighting here.", p.Name)
 	}
 
 	return fmt.Sprintf("%s is here.", p.Name)
 }
 
+// CanSeePlayer returns whether the other player is visible to this player
+func (p *Player) CanSeePlayer(other *Player) bool {
+	return (!other.IsHidden() || p.CanSeeHidden()) &&
+		(!other.IsInvisible() || p.CanSeeInvisible())
+}
+
+// CanSeeMob returns whether the mob is visible to this player
+func (p *Player) CanSeeMob(m *Mob) bool {
+	return (!m.IsHidden() || p.CanSeeHidden()) &&
+		(!m.IsInvisible() || p.CanSeeInvisible())
+}
+
 // NotifyExitingPlayer checks if the exitingPlayer can be seen, and sends a message to the player.
 func (p *Player) NotifyExitingPlayer(exitingPlayer *Player, d Direction) {
-	if p.IsSleeping {
+	if p.IsSleeping() {
 		return
 	}
 
 	if (!exitingPlayer.IsHidden() || p.CanSeeHidden()) &&
 		(!exitingPlayer.IsInvisible() || p.CanSeeInvisible()) {
-		var dString string
-		switch d {
-		case North:
-			dString = "North"
-		case South:
-			dString = "South"
-		case West:
-			dString = "West"
-		case East:
-			dString = "East"
-		}
-		message := exitingPlayer.Name + " left to the " + dString + "."
+		message := fmt.Sprintf("%s leaves %s.", exitingPlayer.Name, directionName(d))
 		p.Notify(message)
 	}
 }
 
 // NotifyEnteringPlayer checks if the enteringPlayer can be seen, and sends a message to the player.
 func (p *Player) NotifyEnteringPlayer(enteringPlayer *Player, d Direction) {
-	if p.IsSleeping {
+	if p.IsSleeping() {
 		return
 	}
 
 	if (!enteringPlayer.IsHidden() || p.CanSeeHidden()) &&
 		(!enteringPlayer.IsInvisible() || p.CanSeeInvisible()) {
-		var dString string
-		switch d {
-		case North:
-			dString = "South"
-		case South:
-			dString = "North"
-		case West:
-			dString = "East"
-		case East:
-			dString = "West"
-		}
-		message := enteringPlayer.Name + " came from the " + dString + "."
+		message := fmt.Sprintf("%s arrives from %s.", enteringPlayer.Name, directionFrom(d.Opposite()))
 		p.Notify(message)
 	}
 }
 
 // Say prints a message for all players on the same room
 func (p *Player) Say(message string) {
-	if p.IsSleeping {
+	if p.IsSleeping() {
 		p.Notify("Is hard to talk while sleeping.")
 		return
 	}
@@ -242,7 +359,7 @@ func (p *Player) Say(message string) {
 
 // Shout prints a message for all players on the same area
 func (p *Player) Shout(message string) {
-	if p.IsSleeping {
+	if p.IsSleeping() {
 		p.Notify("No matter how loud you shout. Nobody can hear you in your dreams.")
 		return
 	}
@@ -252,7 +369,7 @@ func (p *Player) Shout(message string) {
 
 // Hear prints a message from another user that can be heard
 func (p *Player) Hear(playerName, message string, isHidden, isInvisible bool) {
-	if p.IsSleeping {
+	if p.IsSleeping() {
 		return
 	}
 
@@ -264,33 +381,6 @@ func (p *Player) Hear(playerName, message string, isHidden, isInvisible bool) {
 	p.Notify(fmt.Sprintf("%s says: %s", showName, message))
 }
 
-// Sleep puts the player to sleep.
-func (p *Player) Sleep() {
-	if p.IsSleeping {
-		p.Notify("You are already deep asleep.")
-		return
-	}
-
-	if p.IsFighting {
-		p.Notify("Now is not the time to sleep, you are in the middle of a fight!")
-		return
-	}
-
-	p.IsSleeping = true
-	p.Notify("You lay down and start to sleep.")
-}
-
-// Wake wakes up the player.
-func (p *Player) Wake() {
-	if !p.IsSleeping {
-		p.Notify("You are already awake.")
-		return
-	}
-
-	p.IsSleeping = false
-	p.Notify("You wake up and stand up.")
-}
-
 // NewPlayer creates a new player for userID and place it on the starting room
 func (w *World) NewPlayer(userID string) error {
 	user, appErr := w.api.GetUser(userID)
@@ -308,8 +398,13 @@ func (w *World) NewPlayer(userID string) error {
 		MaxHP:       100,
 		CurrentHP:   100,
 		Stats:       make(map[Stat]int),
+		Race:        w.race(DefaultRace),
+		Class:       w.class(DefaultClass),
+		Gold:        StartingGold,
 	}
 
+	w.players[userID].CurrentMana = w.players[userID].MaxMana()
+	w.players[userID].CurrentMoves = w.players[userID].MaxMoves()
 	w.InitPlayer(w.players[userID])
 
 	return nil
@@ -319,18 +414,39 @@ func (w *World) NewPlayer(userID string) error {
 func (w *World) InitPlayer(player *Player) {
 	player.DefaultRoom = w.rooms[w.defaultRoom]
 	player.Notify = func(message string) {
+		if player.Prompt {
+			message += "\n\n" + player.PromptLine()
+		}
 		w.Notify(player.UserID, message)
 	}
 	player.CreateBattle = func(mob *Mob) {
 		w.CreateBattle(player.UserID, mob)
 	}
+	player.AttackPlayer = func(rival *Player) {
+		w.CreatePvPBattle(player.UserID, rival)
+	}
+	player.GetDeathPenalties = func() DeathPenalties {
+		return w.config.Death
+	}
+	player.CurrentRoom.Players[player.UserID] = player
 	player.start()
 }
 
 // Kill starts the combat with the objective
 func (p *Player) Kill(objective string) {
-	mob := p.CurrentRoom.GetMob(objective)
+	if p.tooGhostly() || p.notStanding() {
+		return
+	}
+	mob := p.CurrentRoom.GetMob(objective, p.CanSeeHidden(), p.CanSeeInvisible())
+	if mob == nil && p.CurrentRoom.GetNPC(objective) != nil {
+		p.Notify(fmt.Sprintf("You cannot attack %s.", p.CurrentRoom.GetNPC(objective).DefiniteName()))
+		return
+	}
 	if mob == nil {
+		if rival := p.CurrentRoom.GetPlayer(objective); rival != nil && p.CanSeePlayer(rival) {
+			p.KillPlayer(rival)
+			return
+		}
 		p.Notify(fmt.Sprintf("There is no %s here to kill.", objective))
 		return
 	}
@@ -338,13 +454,6 @@ func (p *Player) Kill(objective string) {
 	p.CreateBattle(mob)
 }
 
-// Dead kills the player and returns it to the default room
-func (p *Player) Dead() {
-	p.CurrentRoom = p.DefaultRoom
-	p.CurrentHP = 1
-	p.Notify(fmt.Sprintf("You almost died! But a light came to your rescue and you find yourself back at %s", p.CurrentRoom.Name))
-}
-
 // start runs the player routine
 func (p *Player) start() {
 	go func() {
@@ -352,11 +461,8 @@ func (p *Player) start() {
 			if p.finishPlayerRoutine() {
 				return
 			}
-			toRegen := max(1, int(float64(p.MaxHP)*0.1))
-			if p.CurrentRoom == p.DefaultRoom {
-				toRegen = toRegen * 3
-			}
-			p.CurrentHP = min(p.MaxHP, p.CurrentHP+toRegen)
+			p.expireEffects(time.Now())
+			p.regenerate()
 			time.Sleep(PlayerRegenTime)
 		}
 	}()
@@ -0,0 +1,241 @@
+package mud
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Put moves an item from the inventory into a container carried by the player or lying on the ground
+func (p *Player) Put(itemKeyword, containerKeyword string) {
+	if p.IsSleeping() {
+		p.Notify("You cannot move things around while sleeping.")
+		return
+	}
+	if p.tooGhostly() {
+		return
+	}
+
+	item := p.Inventory.Find(itemKeyword)
+	if item == nil {
+		p.Notify(fmt.Sprintf("You do not have any %s.", itemKeyword))
+		return
+	}
+
+	container := p.findContainer(containerKeyword)
+	if container == nil {
+		return
+	}
+	c := container.Container
+	switch {
+	case container == item || (item.Container != nil && item.Container.Holds(container)):
+		p.Notify(fmt.Sprintf("You cannot put %s inside itself.", item.Name))
+		return
+	case c.IsClosed:
+		p.Notify(capitalize(container.Name) + " is closed.")
+		return
+	case c.IsFull():
+		p.Notify(fmt.Sprintf("There is no room for %s in %s.", item.Name, container.Name))
+		return
+	case !c.CanBear(item):
+		p.Notify(fmt.Sprintf("%s is too heavy for %s.", capitalize(item.Name), container.Name))
+		return
+	}
+
+	p.Inventory = p.Inventory.Remove(item)
+	c.Contents = append(c.Contents, item)
+	p.Notify(fmt.Sprintf("You put %s in %s.", item.Name, container.Name))
+	p.CurrentRoom.Act(p, fmt.Sprintf("%s puts %s in %s.", p.Name, item.Name, container.Name))
+}
+
+// GetFrom takes an item, or all of them if the keyword is all, out of a container carried by the player or lying on the ground
+func (p *Player) GetFrom(itemKeyword, containerKeyword string) {
+	if p.IsSleeping() {
+		p.Notify("You cannot pick up anything while sleeping.")
+		return
+	}
+	if p.tooGhostly() {
+		return
+	}
+
+	container := p.findContainer(containerKeyword)
+	if container == nil {
+		return
+	}
+	c := container.Container
+	if c.IsClosed {
+		p.Notify(capitalize(container.Name) + " is closed.")
+		return
+	}
+	if container.ownerID != "" && container.ownerID != p.UserID {
+		p.Notify(fmt.Sprintf("You cannot take anything from %s.", container.Name))
+		return
+	}
+
+	items := ItemList{}
+	if strings.EqualFold(itemKeyword, "all") {
+		items = append(items, c.Contents...)
+	} else if item := c.Contents.Find(itemKeyword); item != nil {
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		p.Notify(fmt.Sprintf("There is no %s in %s.", itemKeyword, container.Name))
+		return
+	}
+
+	// Items taken from containers on the ground add weight, the ones from containers carried by the player do not
+	carried := p.Inventory.Find(containerKeyword) == container
+	taken := ItemList{}
+	for _, item := range items {
+		if !carried && !p.CanCarry(item) {
+			p.Notify(fmt.Sprintf("%s is too heavy for you to carry.", capitalize(item.Name)))
+			continue
+		}
+		c.Contents = c.Contents.Remove(item)
+		p.Inventory = append(p.Inventory, item)
+		taken = append(taken, item)
+	}
+	if len(taken) == 0 {
+		return
+	}
+	names := strings.Join(taken.Names(), ", ")
+	p.Notify(fmt.Sprintf("You get %s from %s.", names, container.Name))
+	p.CurrentRoom.Act(p, fmt.Sprintf("%s gets %s from %s.", p.Name, names, container.Name))
+}
+
+// LookIn shows the items inside a container carried by the player or lying on the ground
+func (p *Player) LookIn(keyword string) {
+	if p.IsSleeping() {
+		p.Notify("No matter how hard you look, you see nothing while asleep.")
+		return
+	}
+
+	container := p.findContainer(keyword)
+	if container == nil {
+		return
+	}
+	p.Notify(container.Container.Show(container.Name))
+}
+
+// OpenContainer opens a container carried by the player or lying on the ground
+func (p *Player) OpenContainer(keyword string) {
+	container := p.closeableContainer(keyword)
+	if container == nil {
+		return
+	}
+
+	c := container.Container
+	switch c.State() {
+	case DoorOpen:
+		p.Notify(capitalize(container.Name) + " is already open.")
+	case DoorLocked:
+		p.Notify(capitalize(container.Name) + " is locked.")
+	default:
+		c.IsClosed = false
+		p.Notify(fmt.Sprintf("You open %s.", container.Name))
+		p.CurrentRoom.Act(p, fmt.Sprintf("%s opens %s.", p.Name, container.Name))
+	}
+}
+
+// CloseContainer closes a container carried by the player or lying on the ground
+func (p *Player) CloseContainer(keyword string) {
+	container := p.closeableContainer(keyword)
+	if container == nil {
+		return
+	}
+
+	c := container.Container
+	if c.State() != DoorOpen {
+		p.Notify(capitalize(container.Name) + " is already closed.")
+		return
+	}
+
+	c.IsClosed = true
+	p.Notify(fmt.Sprintf("You close %s.", container.Name))
+	p.CurrentRoom.Act(p, fmt.Sprintf("%s closes %s.", p.Name, container.Name))
+}
+
+// UnlockContainer unlocks a container carried by the player or lying on the ground, if the player has the key
+func (p *Player) UnlockContainer(keyword string) {
+	container := p.closeableContainer(keyword)
+	if container == nil {
+		return
+	}
+
+	c := container.Container
+	if c.State() != DoorLocked {
+		p.Notify(capitalize(container.Name) + " is not locked.")
+		return
+	}
+
+	if !p.HasKey(c.Key) {
+		p.Notify(fmt.Sprintf("You do not have the key for %s.", container.Name))
+		return
+	}
+
+	c.IsLocked = false
+	p.Notify(fmt.Sprintf("*Click*. You unlock %s.", container.Name))
+	p.CurrentRoom.Act(p, fmt.Sprintf("%s unlocks %s.", p.Name, container.Name))
+}
+
+// LockContainer locks a container carried by the player or lying on the ground, if the player has the key
+func (p *Player) LockContainer(keyword string) {
+	container := p.closeableContainer(keyword)
+	if container == nil {
+		return
+	}
+
+	c := container.Container
+	switch c.State() {
+	case DoorLocked:
+		p.Notify(capitalize(container.Name) + " is already locked.")
+		return
+	case DoorOpen:
+		p.Notify(fmt.Sprintf("You have to close %s first.", container.Name))
+		return
+	}
+
+	if !p.HasKey(c.Key) {
+		p.Notify(fmt.Sprintf("You do not have the key for %s.", container.Name))
+		return
+	}
+
+	c.IsLocked = true
+	p.Notify(fmt.Sprintf("*Click*. You lock %s.", container.Name))
+	p.CurrentRoom.Act(p, fmt.Sprintf("%s locks %s.", p.Name, container.Name))
+}
+
+// findContainer looks for a container matching the keyword on the inventory and the ground, in that order,
+// notifying the player if there is none
+func (p *Player) findContainer(keyword string) *Item {
+	item := p.Inventory.Find(keyword)
+	if item == nil {
+		item = p.CurrentRoom.Items.Find(keyword)
+	}
+	if item == nil {
+		p.Notify(fmt.Sprintf("You do not see any %s here.", keyword))
+		return nil
+	}
+	if item.Container == nil {
+		p.Notify(fmt.Sprintf("%s cannot hold anything.", capitalize(item.Name)))
+		return nil
+	}
+	return item
+}
+
+// closeableContainer returns the container matching the keyword if it can be opened and closed, notifying the player otherwise
+func (p *Player) closeableContainer(keyword string) *Item {
+	if p.IsSleeping() {
+		p.Notify("You dream about chests. Lots of chests.")
+		return nil
+	}
+
+	container := p.findContainer(keyword)
+	if container == nil {
+		return nil
+	}
+	if !container.Container.Closeable {
+		p.Notify(fmt.Sprintf("%s cannot be opened or closed.", capitalize(container.Name)))
+		return nil
+	}
+	return container
+}
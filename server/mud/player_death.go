@@ -0,0 +1,177 @@
+package mud
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxDeathRecords is how many deaths are kept on the death log of each player
+	MaxDeathRecords = 10
+	// ResurrectionHP is the percentage of the maximum health points players have when coming back to life
+	ResurrectionHP = 50
+	// WeaknessPenalty is how much strength, constitution and dexterity players lose while weakened after coming back to life
+	WeaknessPenalty = 2
+	// CorpseRunLifespan defines how long the corpse of a player stays on the ground, waiting for its owner
+	CorpseRunLifespan = 30 * time.Minute
+)
+
+// DeathPenalties are the consequences of dying, set by the administrators
+type DeathPenalties struct {
+	// ExperienceLoss is the percentage of the experience earned since the current level that players lose. They never lose levels
+	ExperienceLoss int
+	// CorpseRun denotes whether players leave their inventory on their corpse and have to go back for it
+	CorpseRun bool
+	// Weakness is how long players stay weakened after coming back to life. 0 means they are not weakened
+	Weakness time.Duration
+}
+
+// DefaultDeathPenalties are the consequences of dying when the administrators have not set any
+var DefaultDeathPenalties = DeathPenalties{
+	ExperienceLoss: 10,
+	CorpseRun:      true,
+	Weakness:       5 * time.Minute,
+}
+
+// DeathRecord is an entry of the death log of a player
+type DeathRecord struct {
+	// At is when the player died
+	At time.Time
+	// Room is the name of the room where the player died
+	Room string
+	// Killer is who killed the player
+	Killer string
+	// ExperienceLost is how many experience points the player lost
+	ExperienceLost int
+	// ResurrectedBy is who brought the player back to life. Empty while the player is still a ghost
+	ResurrectedBy string
+}
+
+// Dead kills the player, who loses part of the experience and becomes a ghost until resurrected. With corpse runs, the
+// inventory is left on a corpse that only the player can loot.
+func (p *Player) Dead(killer string) {
+	penalties := p.GetDeathPenalties()
+	room := p.CurrentRoom
+	lost := p.loseExperience(penalties.ExperienceLoss)
+	p.logDeath(&DeathRecord{
+		At:             time.Now(),
+		Room:           room.Name,
+		Killer:         killer,
+		ExperienceLost: lost,
+	})
+
+	room.Act(p, fmt.Sprintf("%s dies, and a pale ghost rises from the body.", p.Name))
+	if penalties.CorpseRun && len(p.Inventory) > 0 {
+		corpse := newCorpse(p.Name, []string{p.Name}, p.Inventory)
+		corpse.ownerID = p.UserID
+		corpse.decayAt = time.Now().Add(CorpseRunLifespan)
+		room.Items = append(room.Items, corpse)
+		p.Inventory = ItemList{}
+	}
+	p.IsGhost = true
+	p.Position = Standing
+	p.CurrentHP = 1
+
+	message := "You died! Your ghost rises from your body."
+	if lost > 0 {
+		message += fmt.Sprintf(" You lose %d experience points.", lost)
+	}
+	if penalties.CorpseRun {
+		message += " Everything you carried lies on your corpse, come back for it before it rots away."
+	}
+	p.Notify(message + " Find your way to a temple, or ask a healer or a mage to bring you back to life.")
+	p.reachTemple()
+}
+
+// loseExperience takes a percentage of the experience earned since the current level, and returns how much was lost
+func (p *Player) loseExperience(percentage int) int {
+	lost := (p.Experience - ExperienceForLevel(p.Level)) * percentage / 100
+	if lost <= 0 {
+		return 0
+	}
+	p.Experience -= lost
+	return lost
+}
+
+// logDeath adds the death to the death log, forgetting the oldest ones
+func (p *Player) logDeath(record *DeathRecord) {
+	p.Deaths = append(p.Deaths, record)
+	if len(p.Deaths) > MaxDeathRecords {
+		p.Deaths = p.Deaths[len(p.Deaths)-MaxDeathRecords:]
+	}
+}
+
+// Resurrect brings the ghost back to life, weakened for a while. By is who performed the resurrection
+func (p *Player) Resurrect(by string) {
+	if !p.IsGhost {
+		return
+	}
+
+	p.IsGhost = false
+	p.CurrentHP = max(1, p.MaxHP*ResurrectionHP/100)
+	if len(p.Deaths) > 0 {
+		p.Deaths[len(p.Deaths)-1].ResurrectedBy = by
+	}
+	if weakness := p.GetDeathPenalties().Weakness; weakness > 0 {
+		p.Effects = append(p.Effects, &Effect{
+			Name:           "weakness",
+			StatsModifiers: Stats{Strength: -WeaknessPenalty, Constitution: -WeaknessPenalty, Dexterity: -WeaknessPenalty},
+			ExpiresAt:      time.Now().Add(weakness),
+		})
+	}
+	p.Notify(fmt.Sprintf("You have been brought back to life by %s. You feel weak, but alive.", by))
+	p.CurrentRoom.Act(p, fmt.Sprintf("The ghost of %s takes flesh again.", p.Name))
+}
+
+// reachTemple resurrects the ghost when the room is a temple
+func (p *Player) reachTemple() {
+	if p.IsGhost && p.CurrentRoom.HasFlag(RoomFlagTemple) {
+		p.Resurrect("the gods")
+	}
+}
+
+// tooGhostly returns whether the player is a ghost, unable to touch anything, notifying the player if so
+func (p *Player) tooGhostly() bool {
+	if !p.IsGhost {
+		return false
+	}
+	p.Notify("Your ghostly hands go right through it. Find a way back to life first.")
+	return true
+}
+
+// ShowDeaths shows the death log of the player
+func (p *Player) ShowDeaths() {
+	if len(p.Deaths) == 0 {
+		p.Notify("You have never died. Keep it that way!")
+		return
+	}
+
+	lines := []string{
+		"| When | Where | Killed by | Experience lost | Resurrected by |",
+		"|:-----|:------|:----------|----------------:|:---------------|",
+	}
+	for i := len(p.Deaths) - 1; i >= 0; i-- {
+		d := p.Deaths[i]
+		resurrectedBy := d.ResurrectedBy
+		if resurrectedBy == "" {
+			resurrectedBy = "Still a ghost"
+		}
+		lines = append(lines, fmt.Sprintf("| %s | %s | %s | %d | %s |", d.At.Format("2006-01-02 15:04"), d.Room, d.Killer, d.ExperienceLost, resurrectedBy))
+	}
+	p.Notify(strings.Join(lines, "\n"))
+}
+
+// expireEffects removes the temporary effects that have worn off, telling the player about them
+func (p *Player) expireEffects(now time.Time) {
+	active, expired := p.Effects.Expire(now)
+	if len(expired) == 0 {
+		return
+	}
+	p.Effects = active
+	for _, e := range expired {
+		if e.Name != "" {
+			p.Notify(fmt.Sprintf("The %s wears off.", e.Name))
+		}
+	}
+}
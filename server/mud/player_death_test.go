@@ -0,0 +1,109 @@
+package mud
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoseExperience(t *testing.T) {
+	tests := []struct {
+		name       string
+		level      int
+		experience int
+		percentage int
+		expected   int
+	}{
+		{"part of the experience of the level", 1, 300, 10, 20},
+		{"disabled", 1, 300, 0, 0},
+		{"never loses levels", 1, 300, 100, 200},
+		{"nothing earned since the level", 2, 400, 50, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Player{Level: tt.level, Experience: tt.experience}
+			assert.Equal(t, tt.expected, p.loseExperience(tt.percentage))
+			assert.Equal(t, tt.experience-tt.expected, p.Experience)
+			assert.GreaterOrEqual(t, p.Experience, ExperienceForLevel(p.Level))
+		})
+	}
+}
+
+func TestDeadAndResurrect(t *testing.T) {
+	newPlayer := func(penalties DeathPenalties, room *Room) *Player {
+		p := &Player{
+			UserID:            "alice",
+			Name:              "alice",
+			Level:             1,
+			Experience:        200,
+			MaxHP:             100,
+			Inventory:         ItemList{{ID: "bread", Name: "a loaf of bread"}},
+			CurrentRoom:       room,
+			Notify:            func(string) {},
+			GetDeathPenalties: func() DeathPenalties { return penalties },
+		}
+		room.Players = map[string]*Player{p.UserID: p}
+		return p
+	}
+
+	t.Run("corpse run", func(t *testing.T) {
+		room := &Room{Name: "Marketplace"}
+		p := newPlayer(DefaultDeathPenalties, room)
+		p.Dead("a stray dog")
+
+		assert.True(t, p.IsGhost)
+		assert.Empty(t, p.Inventory)
+		assert.Equal(t, 190, p.Experience)
+		if assert.Len(t, room.Items, 1) {
+			assert.Equal(t, "alice", room.Items[0].ownerID)
+			assert.Len(t, room.Items[0].Container.Contents, 1)
+		}
+		if assert.Len(t, p.Deaths, 1) {
+			assert.Equal(t, "a stray dog", p.Deaths[0].Killer)
+			assert.Equal(t, "Marketplace", p.Deaths[0].Room)
+			assert.Equal(t, 10, p.Deaths[0].ExperienceLost)
+		}
+
+		p.Resurrect("bob")
+		assert.False(t, p.IsGhost)
+		assert.Equal(t, 50, p.CurrentHP)
+		assert.Equal(t, "bob", p.Deaths[0].ResurrectedBy)
+		if assert.Len(t, p.Effects, 1) {
+			assert.Equal(t, -WeaknessPenalty, p.Effects.GetStatModifiers(Strength))
+			assert.False(t, p.Effects[0].Expired(time.Now()))
+			assert.True(t, p.Effects[0].Expired(time.Now().Add(DefaultDeathPenalties.Weakness+time.Second)))
+		}
+	})
+
+	t.Run("no penalties", func(t *testing.T) {
+		room := &Room{Name: "Marketplace"}
+		p := newPlayer(DeathPenalties{}, room)
+		p.Dead("a stray dog")
+		p.Resurrect("bob")
+
+		assert.Len(t, p.Inventory, 1)
+		assert.Empty(t, room.Items)
+		assert.Equal(t, 200, p.Experience)
+		assert.Empty(t, p.Effects)
+	})
+
+	t.Run("dying in a temple", func(t *testing.T) {
+		room := &Room{Name: "Temple", Flags: []string{RoomFlagTemple}}
+		p := newPlayer(DefaultDeathPenalties, room)
+		p.Dead("a bunny")
+
+		assert.False(t, p.IsGhost)
+		assert.Equal(t, "the gods", p.Deaths[0].ResurrectedBy)
+	})
+
+	t.Run("death log is capped", func(t *testing.T) {
+		room := &Room{Name: "Marketplace"}
+		p := newPlayer(DeathPenalties{}, room)
+		for i := 0; i < MaxDeathRecords+2; i++ {
+			p.Dead("a bunny")
+			p.Resurrect("bob")
+		}
+		assert.Len(t, p.Deaths, MaxDeathRecords)
+	})
+}
@@ -0,0 +1,74 @@
+package mud
+
+import (
+	"fmt"
+)
+
+// Wear equips an item from the inventory, putting back on the inventory whatever was on the same slot.
+// Wielded items and rings go on the free hand when the right one is taken.
+func (p *Player) Wear(keyword string) {
+	if p.IsSleeping() {
+		p.Notify("You cannot wear anything while sleeping.")
+		return
+	}
+
+	item := p.Inventory.Find(keyword)
+	if item == nil {
+		p.Notify(fmt.Sprintf("You do not have any %s.", keyword))
+		return
+	}
+	if item.Equipment == nil {
+		p.Notify(fmt.Sprintf("You cannot wear %s.", item.Name))
+		return
+	}
+
+	slot := p.freeSlot(item.Equipment.Slot)
+	if t := p.forbiddingSlot(slot); t != nil {
+		p.Notify(fmt.Sprintf("As %s %s, you cannot use anything on your %s.", indefiniteArticle(t.Name), t.Name, slot))
+		return
+	}
+
+	p.Inventory = p.Inventory.Remove(item)
+	if old := p.Equip[slot]; old != nil {
+		p.Inventory = append(p.Inventory, old)
+		p.Notify(fmt.Sprintf("You stop using %s.", old.Name))
+	}
+	if p.Equip == nil {
+		p.Equip = make(PlayerEquipment)
+	}
+	p.Equip[slot] = item
+	p.Notify(fmt.Sprintf("You wear %s on your %s.", item.Name, slot))
+	p.CurrentRoom.Act(p, fmt.Sprintf("%s wears %s.", p.Name, item.Name))
+}
+
+// Remove takes off an equipped item, putting it back on the inventory
+func (p *Player) Remove(keyword string) {
+	if p.IsSleeping() {
+		p.Notify("You cannot remove anything while sleeping.")
+		return
+	}
+
+	for slot := Head; slot <= LeftRing; slot++ {
+		item, ok := p.Equip[slot]
+		if !ok || !item.Matches(keyword) {
+			continue
+		}
+		delete(p.Equip, slot)
+		p.Inventory = append(p.Inventory, item)
+		p.Notify(fmt.Sprintf("You stop using %s.", item.Name))
+		p.CurrentRoom.Act(p, fmt.Sprintf("%s stops using %s.", p.Name, item.Name))
+		return
+	}
+	p.Notify(fmt.Sprintf("You are not using any %s.", keyword))
+}
+
+// freeSlot returns the slot where an item for the slot goes: the left hand or ring when the right one is taken
+// and the left one is free and allowed, or the slot itself otherwise
+func (p *Player) freeSlot(slot EquipmentSlot) EquipmentSlot {
+	left := map[EquipmentSlot]EquipmentSlot{RightHand: LeftHand, RightRing: LeftRing}
+	other, ok := left[slot]
+	if !ok || p.Equip[slot] == nil || p.Equip[other] != nil || p.forbiddingSlot(other) != nil {
+		return slot
+	}
+	return other
+}
@@ -0,0 +1,243 @@
+package mud
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Group is a party of players that travel and fight together. The members follow the leader, join the battles of the
+// leader and split the experience of the mobs they kill.
+type Group struct {
+	// Leader is the player who created the group
+	Leader *Player
+	// Members are all the players of the group, leader included
+	Members []*Player
+}
+
+// Has returns whether the player is a member of the group
+func (g *Group) Has(p *Player) bool {
+	for _, m := range g.Members {
+		if m == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Tell sends a message to all the members of the group
+func (g *Group) Tell(message string) {
+	for _, m := range g.Members {
+		m.Notify(message)
+	}
+}
+
+// remove takes the player out of the group, disbanding it if only the leader is left
+func (g *Group) remove(p *Player) {
+	for i, m := range g.Members {
+		if m == p {
+			g.Members = append(g.Members[:i], g.Members[i+1:]...)
+			break
+		}
+	}
+	p.group = nil
+	if len(g.Members) <= 1 {
+		for _, m := range g.Members {
+			m.group = nil
+		}
+		g.Members = nil
+	}
+}
+
+// Show returns the status of the members of the group
+func (g *Group) Show() string {
+	lines := []string{
+		"| Member | Level | HP | Room |",
+		"|:-------|------:|---:|:-----|",
+	}
+	for _, m := range g.Members {
+		name := m.Name
+		if m == g.Leader {
+			name += " (leader)"
+		}
+		lines = append(lines, fmt.Sprintf("| %s | %d | %d/%d | %s |", name, m.Level, m.CurrentHP, m.MaxHP, m.CurrentRoom.Name))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Follow makes the player follow another player in the same room when moving. Following oneself stops following.
+func (p *Player) Follow(name string) {
+	if strings.EqualFold(name, "self") || strings.EqualFold(name, p.Name) {
+		if p.following == nil {
+			p.Notify("You are not following anyone.")
+			return
+		}
+		p.stopFollowing()
+		return
+	}
+
+	leader := p.CurrentRoom.GetPlayer(name)
+	if leader == nil || !p.CanSeePlayer(leader) {
+		p.Notify(fmt.Sprintf("There is no %s here.", name))
+		return
+	}
+	if leader == p.following {
+		p.Notify(fmt.Sprintf("You are already following %s.", leader.Name))
+		return
+	}
+	for f := leader; f != nil; f = f.following {
+		if f == p {
+			p.Notify(fmt.Sprintf("You cannot follow %s, who is already following you.", leader.Name))
+			return
+		}
+	}
+
+	if p.following != nil {
+		p.stopFollowing()
+	}
+	p.following = leader
+	p.Notify(fmt.Sprintf("You start following %s.", leader.Name))
+	leader.Notify(fmt.Sprintf("%s starts following you.", p.Name))
+}
+
+// stopFollowing makes the player stop following anyone, leaving the group if the player was a member
+func (p *Player) stopFollowing() {
+	leader := p.following
+	p.following = nil
+	p.Notify(fmt.Sprintf("You stop following %s.", leader.Name))
+	leader.Notify(fmt.Sprintf("%s stops following you.", p.Name))
+	if g := p.group; g != nil && g.Leader == leader {
+		g.remove(p)
+		g.Tell(fmt.Sprintf("%s leaves the group.", p.Name))
+		p.Notify("You leave the group.")
+	}
+}
+
+// AddToGroup adds a player who is following the leader to the group of the leader, creating it if needed
+func (p *Player) AddToGroup(name string) {
+	if p.group != nil && p.group.Leader != p {
+		p.Notify("Only the leader of the group can add new members.")
+		return
+	}
+
+	member := p.CurrentRoom.GetPlayer(name)
+	if member == nil || !p.CanSeePlayer(member) {
+		p.Notify(fmt.Sprintf("There is no %s here.", name))
+		return
+	}
+	switch {
+	case member == p:
+		p.Notify("You are always part of your own group.")
+		return
+	case p.group != nil && p.group.Has(member):
+		p.Notify(fmt.Sprintf("%s is already in your group.", member.Name))
+		return
+	case member.following != p:
+		p.Notify(fmt.Sprintf("%s has to follow you first.", member.Name))
+		return
+	case member.group != nil:
+		p.Notify(fmt.Sprintf("%s is already in another group.", member.Name))
+		return
+	}
+
+	if p.group == nil {
+		p.group = &Group{Leader: p, Members: []*Player{p}}
+	}
+	p.group.Tell(fmt.Sprintf("%s joins the group.", member.Name))
+	p.group.Members = append(p.group.Members, member)
+	member.group = p.group
+	member.Notify(fmt.Sprintf("You join the group of %s.", p.Name))
+}
+
+// Ungroup removes a member from the group of the leader. Without a name, the leader disbands the group and
+// any other member leaves it.
+func (p *Player) Ungroup(name string) {
+	g := p.group
+	if g == nil {
+		p.Notify("You are not in a group.")
+		return
+	}
+
+	if name == "" {
+		if g.Leader != p {
+			p.stopFollowing()
+			return
+		}
+		g.Tell(fmt.Sprintf("%s disbands the group.", p.Name))
+		for _, m := range append([]*Player{}, g.Members...) {
+			m.group = nil
+			if m.following == p {
+				m.following = nil
+			}
+		}
+		g.Members = nil
+		return
+	}
+
+	if g.Leader != p {
+		p.Notify("Only the leader of the group can remove members.")
+		return
+	}
+	var member *Player
+	for _, m := range g.Members {
+		if m != p && strings.EqualFold(m.Name, name) {
+			member = m
+		}
+	}
+	if member == nil {
+		p.Notify(fmt.Sprintf("There is no %s in your group.", name))
+		return
+	}
+	g.remove(member)
+	member.following = nil
+	member.Notify(fmt.Sprintf("%s removes you from the group.", p.Name))
+	g.Tell(fmt.Sprintf("%s removes %s from the group.", p.Name, member.Name))
+}
+
+// GroupTell sends a message to all the members of the group of the player
+func (p *Player) GroupTell(message string) {
+	if p.group == nil {
+		p.Notify("You are not in a group.")
+		return
+	}
+	for _, m := range p.group.Members {
+		if m == p {
+			continue
+		}
+		m.Notify(fmt.Sprintf("%s tells the group: %s", p.Name, message))
+	}
+	p.Notify("You tell the group: " + message)
+}
+
+// ShowGroup shows the HP and location of all the members of the group of the player
+func (p *Player) ShowGroup() {
+	if p.group == nil {
+		p.Notify("You are not in a group.")
+		return
+	}
+	p.Notify(p.group.Show())
+}
+
+// followers returns the players in the room who follow this player
+func (p *Player) followers() []*Player {
+	followers := []*Player{}
+	for _, f := range p.CurrentRoom.Players {
+		if f.following == p {
+			followers = append(followers, f)
+		}
+	}
+	return followers
+}
+
+// assistants returns the members of the group led by the player who are in the same room, standing and free to fight
+func (p *Player) assistants() []*Player {
+	assistants := []*Player{}
+	if p.group == nil || p.group.Leader != p {
+		return assistants
+	}
+	for _, m := range p.group.Members {
+		if m != p && m.CurrentRoom == p.CurrentRoom && m.IsAvailable() {
+			assistants = append(assistants, m)
+		}
+	}
+	return assistants
+}
@@ -0,0 +1,170 @@
+package mud
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Get picks up an item from the ground
+func (p *Player) Get(keyword string) {
+	if p.IsSleeping() {
+		p.Notify("You cannot pick up anything while sleeping.")
+		return
+	}
+	if p.tooGhostly() {
+		return
+	}
+
+	item := p.CurrentRoom.Items.Find(keyword)
+	if item == nil {
+		p.Notify(fmt.Sprintf("There is no %s here.", keyword))
+		return
+	}
+	if item.NoTake {
+		p.Notify(fmt.Sprintf("You cannot pick up %s.", item.Name))
+		return
+	}
+	if !p.CanCarry(item) {
+		p.Notify(fmt.Sprintf("%s is too heavy for you to carry.", capitalize(item.Name)))
+		return
+	}
+
+	p.CurrentRoom.Items = p.CurrentRoom.Items.Remove(item)
+	p.Inventory = append(p.Inventory, item)
+	p.Notify(fmt.Sprintf("You pick up %s.", item.Name))
+	p.CurrentRoom.Act(p, fmt.Sprintf("%s picks up %s.", p.Name, item.Name))
+}
+
+// Drop leaves an item from the inventory on the ground
+func (p *Player) Drop(keyword string) {
+	if p.IsSleeping() {
+		p.Notify("You cannot drop anything while sleeping.")
+		return
+	}
+
+	item := p.Inventory.Find(keyword)
+	if item == nil {
+		p.Notify(fmt.Sprintf("You do not have any %s.", keyword))
+		return
+	}
+
+	p.Inventory = p.Inventory.Remove(item)
+	p.CurrentRoom.Items = append(p.CurrentRoom.Items, item)
+	p.Notify(fmt.Sprintf("You drop %s.", item.Name))
+	p.CurrentRoom.Act(p, fmt.Sprintf("%s drops %s.", p.Name, item.Name))
+}
+
+// ShowInventory shows the items carried by the player
+func (p *Player) ShowInventory() {
+	if len(p.Inventory) == 0 {
+		p.Notify("You are not carrying anything.")
+		return
+	}
+
+	p.Notify("You are carrying:\n* " + strings.Join(p.Inventory.Names(), "\n* ") + "\n\n" + p.ShowWeight())
+}
+
+// OpenDoor opens the door in direction d
+func (p *Player) OpenDoor(d Direction) {
+	door := p.visibleDoor(d)
+	if door == nil {
+		return
+	}
+
+	switch door.State() {
+	case DoorOpen:
+		p.Notify("The door is already open.")
+	case DoorLocked:
+		p.Notify("The door is locked.")
+	default:
+		p.CurrentRoom.SetDoorState(d, DoorOpen)
+		p.Notify("You open the door.")
+		p.CurrentRoom.Act(p, fmt.Sprintf("%s opens the door to the %s.", p.Name, directionName(d)))
+	}
+}
+
+// CloseDoor closes the door in direction d
+func (p *Player) CloseDoor(d Direction) {
+	door := p.visibleDoor(d)
+	if door == nil {
+		return
+	}
+
+	if door.State() != DoorOpen {
+		p.Notify("The door is already closed.")
+		return
+	}
+
+	p.CurrentRoom.SetDoorState(d, DoorClosed)
+	p.Notify("You close the door.")
+	p.CurrentRoom.Act(p, fmt.Sprintf("%s closes the door to the %s.", p.Name, directionName(d)))
+}
+
+// UnlockDoor unlocks the door in direction d, if the player has the key
+func (p *Player) UnlockDoor(d Direction) {
+	door := p.visibleDoor(d)
+	if door == nil {
+		return
+	}
+
+	if door.State() != DoorLocked {
+		p.Notify("The door is not locked.")
+		return
+	}
+
+	if !p.HasKey(door.Key()) {
+		p.Notify("You do not have the key for this door.")
+		return
+	}
+
+	p.CurrentRoom.SetDoorState(d, DoorClosed)
+	p.Notify("*Click*. You unlock the door.")
+	p.CurrentRoom.Act(p, fmt.Sprintf("%s unlocks the door to the %s.", p.Name, directionName(d)))
+}
+
+// LockDoor locks the door in direction d, if the player has the key
+func (p *Player) LockDoor(d Direction) {
+	door := p.visibleDoor(d)
+	if door == nil {
+		return
+	}
+
+	switch door.State() {
+	case DoorLocked:
+		p.Notify("The door is already locked.")
+		return
+	case DoorOpen:
+		p.Notify("You have to close the door first.")
+		return
+	}
+
+	if !p.HasKey(door.Key()) {
+		p.Notify("You do not have the key for this door.")
+		return
+	}
+
+	p.CurrentRoom.SetDoorState(d, DoorLocked)
+	p.Notify("*Click*. You lock the door.")
+	p.CurrentRoom.Act(p, fmt.Sprintf("%s locks the door to the %s.", p.Name, directionName(d)))
+}
+
+// HasKey returns whether the player carries the key. Empty keys do not need any item.
+// Keys inside containers do not count, they must be at hand.
+func (p *Player) HasKey(key string) bool {
+	return key == "" || p.Inventory.Count(key) > 0
+}
+
+// visibleDoor returns the door in direction d if the player can see it, notifying the player otherwise
+func (p *Player) visibleDoor(d Direction) *RoomDoor {
+	if p.IsSleeping() {
+		p.Notify("You dream about doors. Lots of doors.")
+		return nil
+	}
+
+	if !p.CanSeeDoor(d) {
+		p.Notify("There is no door in that direction.")
+		return nil
+	}
+
+	return p.CurrentRoom.GetDoor(d)
+}
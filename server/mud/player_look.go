@@ -0,0 +1,102 @@
+package mud
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LookAt shows the description of a mob, player, item or detail of the room matching the keyword
+func (p *Player) LookAt(keyword string) {
+	if p.IsSleeping() {
+		p.Notify("No matter how hard you look, you see nothing while asleep.")
+		return
+	}
+
+	if mob := p.CurrentRoom.GetMob(keyword, p.CanSeeHidden(), p.CanSeeInvisible()); mob != nil {
+		p.Notify(mob.Look())
+		return
+	}
+
+	if npc := p.CurrentRoom.GetNPC(keyword); npc != nil {
+		p.Notify(npc.Look())
+		return
+	}
+
+	if other := p.CurrentRoom.GetPlayer(keyword); other != nil && (other == p || p.CanSeePlayer(other)) {
+		p.Notify(other.look())
+		return
+	}
+
+	if item := p.findItem(keyword); item != nil {
+		p.Notify(item.Look())
+		return
+	}
+
+	if extra := p.CurrentRoom.GetExtraDescription(keyword); extra != nil {
+		p.Notify(extra.Description)
+		return
+	}
+
+	p.Notify(fmt.Sprintf("You do not see any %s here.", keyword))
+}
+
+// LookDirection shows the name of the room in direction d, if the way is visible and open
+func (p *Player) LookDirection(d Direction) {
+	if p.IsSleeping() {
+		p.Notify("No matter how hard you look, you see nothing while asleep.")
+		return
+	}
+
+	if p.CanSeeDoor(d) && p.CurrentRoom.GetDoor(d).State() != DoorOpen {
+		p.Notify(fmt.Sprintf("The door to the %s is closed.", directionName(d)))
+		return
+	}
+
+	if !p.CurrentRoom.CanMove(d, p.CanSeeHidden(), p.CanSeeInvisible()) {
+		p.Notify("You see nothing special in that direction.")
+		return
+	}
+
+	p.Notify(fmt.Sprintf("To the %s you see: %s", directionName(d), p.CurrentRoom.GetNeighbourRoom(d).Name))
+}
+
+// Examine shows the description and the properties of an item carried, worn or lying on the ground
+func (p *Player) Examine(keyword string) {
+	if p.IsSleeping() {
+		p.Notify("You cannot examine anything while sleeping.")
+		return
+	}
+
+	item := p.findItem(keyword)
+	if item == nil {
+		p.Notify(fmt.Sprintf("You do not see any %s here.", keyword))
+		return
+	}
+
+	p.Notify(item.Examine())
+}
+
+// findItem looks for an item matching the keyword on the inventory, the equipment and the ground, in that order
+func (p *Player) findItem(keyword string) *Item {
+	if item := p.Inventory.Find(keyword); item != nil {
+		return item
+	}
+	for _, item := range p.Equip {
+		if item.Matches(keyword) {
+			return item
+		}
+	}
+	return p.CurrentRoom.Items.Find(keyword)
+}
+
+// look returns the description shown when someone looks at the player
+func (p *Player) look() string {
+	lines := []string{fmt.Sprintf("%s %s.", p.Name, healthCondition(p.CurrentHP, p.MaxHP))}
+	if p.IsSleeping() {
+		lines = append(lines, p.Name+" is sleeping.")
+	}
+	if equip := p.Equip.Show(); len(equip) > 0 {
+		lines = append(lines, "", p.Name+" is using:", "* "+strings.Join(equip, "\n* "))
+	}
+	return strings.Join(lines, "\n")
+}
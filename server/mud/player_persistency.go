@@ -29,18 +29,20 @@ type JSONPlayer struct {
 	Name string
 	// Stats are the base stats of the character
 	Stats Stats
-	// Class is the character class
-	Class PlayerClass
-	// Race is the character race
-	Race Race
+	// Class is the ID of the character class. Old saves stored it by number
+	Class ClassID
+	// Race is the ID of the character race. Old saves stored it by number
+	Race RaceID
 	// Level is the current experience leve
 	Level int
 	// Experience how many experience points the player has. It is used for levelling up
 	Experience int
-	// IsSleeping shows whether the player is sleeping and should receive messages from the bot or not
-	IsSleeping bool
+	// IsSleeping shows whether the player was sleeping. Only read from saves older than positions
+	IsSleeping bool `json:",omitempty"`
+	// Position is the posture of the player. Battles do not survive restarts, so fighting players are saved standing
+	Position Position
 	// Inventory contains all the items carried by the character
-	Inventory []*Item
+	Inventory ItemList
 	// Equip contains the currently equipped items
 	Equip PlayerEquipment
 	// Effects show all the magical effects that the character is currently under
@@ -51,6 +53,24 @@ type JSONPlayer struct {
 	MaxHP int
 	// CurrentHP denotes the current Health points
 	CurrentHP int
+	// CurrentMana denotes the current mana. Saves older than mana do not have it, and start with it full
+	CurrentMana *int
+	// CurrentMoves denotes the current movement points. Saves older than movement points do not have them, and start with them full
+	CurrentMoves *int
+	// Prompt denotes whether the health, mana and movement points are shown after every notification
+	Prompt bool
+	// Gold is how much money the player carries
+	Gold int
+	// Flags are the story flags set on the player by the dialogues with NPCs
+	Flags map[string]bool
+	// Quests contains the progress on the quests the player is working on or completed, keyed by quest ID
+	Quests map[string]*PlayerQuest
+	// PvP denotes whether the player accepts fighting other players outside arenas
+	PvP bool
+	// IsGhost denotes whether the player died and is waiting to be brought back to life
+	IsGhost bool
+	// Deaths is the log of the last deaths of the player
+	Deaths []*DeathRecord
 }
 
 // autoSave stores the player information periodically into the persistant memory
@@ -111,21 +131,34 @@ func playerListKey() string {
 }
 
 func playerToJSONPlayer(in *Player) *JSONPlayer {
+	mana, moves := in.CurrentMana, in.CurrentMoves
 	out := &JSONPlayer{
-		UserID:      in.UserID,
-		Name:        in.Name,
-		Stats:       in.Stats,
-		Class:       in.Class,
-		Race:        in.Race,
-		Level:       in.Level,
-		Experience:  in.Experience,
-		IsSleeping:  in.IsSleeping,
-		Inventory:   in.Inventory,
-		Equip:       in.Equip,
-		Effects:     in.Effects,
-		MaxHP:       in.MaxHP,
-		CurrentHP:   in.CurrentHP,
-		CurrentRoom: in.CurrentRoom.ID,
+		UserID:       in.UserID,
+		Name:         in.Name,
+		Stats:        in.Stats,
+		Class:        ClassID(in.Class.ID),
+		Race:         RaceID(in.Race.ID),
+		Level:        in.Level,
+		Experience:   in.Experience,
+		Position:     in.Position,
+		Inventory:    in.Inventory,
+		Equip:        in.Equip,
+		Effects:      in.Effects,
+		MaxHP:        in.MaxHP,
+		CurrentHP:    in.CurrentHP,
+		CurrentMana:  &mana,
+		CurrentMoves: &moves,
+		Prompt:       in.Prompt,
+		CurrentRoom:  in.CurrentRoom.ID,
+		Gold:         in.Gold,
+		Flags:        in.Flags,
+		Quests:       in.Quests,
+		PvP:          in.PvP,
+		IsGhost:      in.IsGhost,
+		Deaths:       in.Deaths,
+	}
+	if in.IsFighting() {
+		out.Position = Standing
 	}
 	return out
 }
@@ -140,18 +173,39 @@ func (w *World) jsonPlayerToPlayer(in *JSONPlayer) *Player {
 		UserID:      in.UserID,
 		Name:        in.Name,
 		Stats:       in.Stats,
-		Class:       in.Class,
-		Race:        in.Race,
+		Class:       w.class(in.Class),
+		Race:        w.race(in.Race),
 		Level:       in.Level,
 		Experience:  in.Experience,
-		IsSleeping:  in.IsSleeping,
+		Position:    in.Position,
 		Inventory:   in.Inventory,
 		Equip:       in.Equip,
 		Effects:     in.Effects,
 		MaxHP:       in.MaxHP,
 		CurrentHP:   in.CurrentHP,
+		Prompt:      in.Prompt,
 		CurrentRoom: room,
+		Gold:        in.Gold,
+		Flags:       in.Flags,
+		Quests:      in.Quests,
+		PvP:         in.PvP,
+		IsGhost:     in.IsGhost,
+		Deaths:      in.Deaths,
+	}
+	if in.IsSleeping {
+		out.Position = Sleeping
 	}
+	out.CurrentMana = savedOrFull(in.CurrentMana, out.MaxMana())
+	out.CurrentMoves = savedOrFull(in.CurrentMoves, out.MaxMoves())
+	w.relinkPlayerQuests(out)
 	w.InitPlayer(out)
 	return out
 }
+
+// savedOrFull returns the saved amount of a resource, or the maximum if the save is older than the resource
+func savedOrFull(saved *int, max int) int {
+	if saved == nil {
+		return max
+	}
+	return *saved
+}
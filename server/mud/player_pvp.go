@@ -0,0 +1,86 @@
+package mud
+
+import "fmt"
+
+// SetPvP sets whether the player accepts fighting other players outside arenas
+func (p *Player) SetPvP(on bool) {
+	if p.IsFighting() {
+		p.Notify("You cannot change your mind in the middle of a fight!")
+		return
+	}
+	if p.PvP == on {
+		p.ShowPvP()
+		return
+	}
+
+	p.PvP = on
+	if on {
+		p.Notify("You are now willing to fight other players. Beware, they can attack you too!")
+		return
+	}
+	p.Notify("You no longer fight other players, except in arenas.")
+}
+
+// ShowPvP tells the player whether other players can attack them outside arenas
+func (p *Player) ShowPvP() {
+	if p.PvP {
+		p.Notify("You fight other players. Use pvp off to stop.")
+		return
+	}
+	p.Notify("You do not fight other players, except in arenas. Use pvp on to start.")
+}
+
+// KillPlayer starts a battle with another player in the same room, if both are allowed to fight
+func (p *Player) KillPlayer(rival *Player) {
+	if reason := p.pvpBlocker(rival); reason != "" {
+		p.Notify(reason)
+		return
+	}
+	p.AttackPlayer(rival)
+}
+
+// pvpBlocker returns why the player cannot attack the rival, or an empty string if nothing prevents it.
+// Safe rooms never allow fighting other players, arenas always do, and anywhere else both players have to accept it.
+func (p *Player) pvpBlocker(rival *Player) string {
+	switch {
+	case rival == p:
+		return "You cannot attack yourself."
+	case rival.IsGhost:
+		return fmt.Sprintf("%s is a ghost, beyond any harm.", rival.Name)
+	case p.CurrentRoom.HasFlag(RoomFlagSafe):
+		return "This is a safe place. Nobody can fight other players here."
+	case p.group != nil && p.group.Has(rival):
+		return "You cannot attack a member of your own group."
+	case p.CurrentRoom.IsArena():
+		return ""
+	case !p.PvP:
+		return "You do not fight other players. Use pvp on if you want to."
+	case !rival.PvP:
+		return fmt.Sprintf("%s does not fight other players.", rival.Name)
+	}
+	return ""
+}
+
+// Defeated handles the death of the player at the hands of other players. In arenas nothing is lost, and the player
+// is carried to the recall room of the arena. Anywhere else, the inventory is left on a corpse for the winners before
+// dying as usual.
+func (p *Player) Defeated(killer string) {
+	room := p.CurrentRoom
+	if room.IsArena() {
+		p.CurrentHP = 1
+		room.Act(p, fmt.Sprintf("%s is carried out of the fight.", p.Name))
+		delete(room.Players, p.UserID)
+		p.CurrentRoom = room.area.RecallRoom
+		p.CurrentRoom.Players[p.UserID] = p
+		p.Notify(fmt.Sprintf("You have been defeated! You wake up at %s, bruised but with all your belongings.", p.CurrentRoom.Name))
+		p.CurrentRoom.Act(p, fmt.Sprintf("%s is carried in, bruised after a fight.", p.Name))
+		return
+	}
+
+	if len(p.Inventory) > 0 {
+		room.Items = append(room.Items, newCorpse(p.Name, []string{p.Name}, p.Inventory))
+		p.Inventory = ItemList{}
+		room.Act(p, fmt.Sprintf("%s falls, leaving everything behind.", p.Name))
+	}
+	p.Dead(killer)
+}
@@ -0,0 +1,47 @@
+package mud
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPvPBlocker(t *testing.T) {
+	street := &Room{}
+	temple := &Room{Flags: []string{RoomFlagSafe}}
+	pit := &Room{area: &Area{Flags: []string{AreaFlagArena}}}
+	lobby := &Room{Flags: []string{RoomFlagSafe}, area: pit.area}
+
+	tests := []struct {
+		name      string
+		room      *Room
+		pvp       bool
+		rivalPvP  bool
+		sameGroup bool
+		allowed   bool
+	}{
+		{"both opted in", street, true, true, false, true},
+		{"attacker did not opt in", street, false, true, false, false},
+		{"rival did not opt in", street, true, false, false, false},
+		{"safe room", temple, true, true, false, false},
+		{"arena", pit, false, false, false, true},
+		{"safe room inside an arena", lobby, false, false, false, false},
+		{"same group", pit, true, true, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Player{Name: "alice", CurrentRoom: tt.room, PvP: tt.pvp}
+			rival := &Player{Name: "bob", CurrentRoom: tt.room, PvP: tt.rivalPvP}
+			if tt.sameGroup {
+				p.group = &Group{Leader: p, Members: []*Player{p, rival}}
+				rival.group = p.group
+			}
+			assert.Equal(t, tt.allowed, p.pvpBlocker(rival) == "")
+		})
+	}
+
+	t.Run("cannot attack oneself", func(t *testing.T) {
+		p := &Player{CurrentRoom: pit}
+		assert.NotEmpty(t, p.pvpBlocker(p))
+	})
+}
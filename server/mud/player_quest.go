@@ -0,0 +1,212 @@
+package mud
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// QuestNotStarted is the state of the quests the player never started or abandoned
+	QuestNotStarted = "not_started"
+	// QuestActive is the state of the quests the player is working on
+	QuestActive = "active"
+	// QuestCompleted is the state of the quests the player completed
+	QuestCompleted = "completed"
+)
+
+// QuestState returns whether the player has not started, is working on or has completed the quest
+func (p *Player) QuestState(questID string) string {
+	pq, ok := p.Quests[questID]
+	switch {
+	case !ok:
+		return QuestNotStarted
+	case pq.Completed:
+		return QuestCompleted
+	default:
+		return QuestActive
+	}
+}
+
+// StartQuest gives the quest to the player, unless the player is already working on it or completed it
+func (p *Player) StartQuest(q *Quest) {
+	if p.QuestState(q.ID) != QuestNotStarted {
+		return
+	}
+	if p.Quests == nil {
+		p.Quests = make(map[string]*PlayerQuest)
+	}
+	p.Quests[q.ID] = &PlayerQuest{quest: q}
+	p.Notify(fmt.Sprintf("New quest: %s\n%s", q.Name, q.Objectives[0].Show()))
+}
+
+// ShowQuests shows the quests the player is working on and the ones completed
+func (p *Player) ShowQuests() {
+	active := p.activeQuests()
+	completed := []string{}
+	for _, pq := range p.Quests {
+		if pq.Completed && pq.quest != nil {
+			completed = append(completed, pq.quest.Name)
+		}
+	}
+	sort.Strings(completed)
+
+	if len(active) == 0 && len(completed) == 0 {
+		p.Notify("You have no quests. Talk to the people of the world to find some.")
+		return
+	}
+
+	lines := []string{"Active quests:"}
+	if len(active) == 0 {
+		lines = append(lines, "None")
+	}
+	for i, pq := range active {
+		lines = append(lines, fmt.Sprintf("%d. %s: %s", i+1, pq.quest.Name, pq.objective().Show()))
+	}
+	if len(completed) > 0 {
+		lines = append(lines, "", "Completed quests:", "* "+strings.Join(completed, "\n* "))
+	}
+	p.Notify(strings.Join(lines, "\n"))
+}
+
+// QuestInfo shows the objectives and rewards of a quest, referred by its number on the quest list, ID or name
+func (p *Player) QuestInfo(keyword string) {
+	pq := p.findQuest(keyword)
+	if pq == nil {
+		p.Notify(fmt.Sprintf("You have no quest %s.", keyword))
+		return
+	}
+	p.Notify(pq.Show())
+}
+
+// AbandonQuest drops an active quest, losing all the progress
+func (p *Player) AbandonQuest(keyword string) {
+	pq := p.findQuest(keyword)
+	if pq == nil || pq.Completed {
+		p.Notify(fmt.Sprintf("You are not working on any quest %s.", keyword))
+		return
+	}
+	delete(p.Quests, pq.quest.ID)
+	p.Notify(fmt.Sprintf("You abandon the quest %s.", pq.quest.Name))
+}
+
+// QuestEvent advances the active quests of the player whose current objective is of type kind on target:
+// the mob ID for kill, the room ID for reach and the full NPC ID for talk and deliver
+func (p *Player) QuestEvent(kind, target string) {
+	for _, pq := range p.activeQuests() {
+		o := pq.objective()
+		if o.Type != kind {
+			continue
+		}
+
+		switch kind {
+		case ObjectiveKill:
+			if o.MobID != target {
+				continue
+			}
+			pq.Count++
+			if pq.Count < o.Count {
+				p.Notify(fmt.Sprintf("Quest %s: %s (%d/%d)", pq.quest.Name, o.Show(), pq.Count, o.Count))
+				continue
+			}
+		case ObjectiveReach:
+			if o.Room != target {
+				continue
+			}
+		case ObjectiveTalk:
+			if o.NPC != target {
+				continue
+			}
+		case ObjectiveDeliver:
+			if o.NPC != target {
+				continue
+			}
+			item := p.Inventory.FindID(o.ItemID)
+			if item == nil {
+				continue
+			}
+			p.Inventory = p.Inventory.Remove(item)
+			p.Notify(fmt.Sprintf("You hand over %s.", item.Name))
+		}
+		p.advanceQuest(pq)
+	}
+}
+
+// advanceQuest moves to the next objective of the quest, completing it and giving the reward after the last one
+func (p *Player) advanceQuest(pq *PlayerQuest) {
+	pq.Step++
+	pq.Count = 0
+	if next := pq.objective(); next != nil {
+		p.Notify(fmt.Sprintf("Quest %s updated: %s", pq.quest.Name, next.Show()))
+		return
+	}
+
+	pq.Completed = true
+	reward := pq.quest.Reward
+	p.Notify(fmt.Sprintf("Quest completed: %s! You get %s.", pq.quest.Name, reward.Show()))
+	p.Gold += reward.Gold
+	for _, item := range reward.items {
+		p.Inventory = append(p.Inventory, item.Spawn())
+	}
+	p.GainExperience(reward.Experience)
+}
+
+// activeQuests returns the quests the player is working on, sorted by name. Quests that do not exist anymore are skipped
+func (p *Player) activeQuests() []*PlayerQuest {
+	active := []*PlayerQuest{}
+	for _, pq := range p.Quests {
+		if !pq.Completed && pq.quest != nil {
+			active = append(active, pq)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].quest.Name < active[j].quest.Name
+	})
+	return active
+}
+
+// findQuest returns the quest of the player referred by its number on the active quest list, ID or name
+func (p *Player) findQuest(keyword string) *PlayerQuest {
+	if n, err := strconv.Atoi(keyword); err == nil {
+		active := p.activeQuests()
+		if n < 1 || n > len(active) {
+			return nil
+		}
+		return active[n-1]
+	}
+	for id, pq := range p.Quests {
+		if pq.quest == nil {
+			continue
+		}
+		if strings.EqualFold(id, keyword) || strings.EqualFold(pq.quest.Name, keyword) {
+			return pq
+		}
+	}
+	return nil
+}
+
+// relinkQuests points the progress of the player to the loaded quests. The progress on quests that do not exist anymore
+// is kept, but ignored until they are back. Returns the IDs of those quests
+func (p *Player) relinkQuests(quests map[string]*Quest) []string {
+	missing := []string{}
+	for id, pq := range p.Quests {
+		q, ok := quests[id]
+		pq.quest = q
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		if !pq.Completed && pq.Step >= len(q.Objectives) {
+			pq.Step = len(q.Objectives) - 1
+		}
+	}
+	return missing
+}
+
+// relinkPlayerQuests points the progress of the player to the quests of the world, warning about the ones that do not exist anymore
+func (w *World) relinkPlayerQuests(p *Player) {
+	for _, id := range p.relinkQuests(w.questsDB) {
+		w.api.LogWarn("quest not found, keeping the progress of the player until it is back", "user_id", p.UserID, "quest_id", id)
+	}
+}
@@ -0,0 +1,34 @@
+package mud
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelinkQuests(t *testing.T) {
+	rats := &Quest{ID: "rats", Name: "Rats", Objectives: []*Objective{{Type: ObjectiveKill, MobID: "rat", Count: 2}}}
+	p := &Player{Quests: map[string]*PlayerQuest{
+		"rats":    {Step: 3},
+		"renamed": {Step: 1, Count: 4},
+		"done":    {Completed: true},
+	}}
+	notifications := []string{}
+	p.Notify = func(message string) { notifications = append(notifications, message) }
+
+	missing := p.relinkQuests(map[string]*Quest{"rats": rats})
+	assert.ElementsMatch(t, []string{"renamed", "done"}, missing)
+	assert.Len(t, p.Quests, 3, "the progress on missing quests is kept")
+	assert.Equal(t, 0, p.Quests["rats"].Step)
+	assert.Equal(t, &PlayerQuest{Step: 1, Count: 4}, p.Quests["renamed"])
+	assert.Equal(t, QuestCompleted, p.QuestState("done"))
+
+	p.QuestEvent(ObjectiveKill, "rat")
+	p.ShowQuests()
+	p.QuestInfo("renamed")
+	assert.Equal(t, []string{
+		"Quest Rats: Kill 2 rat (1/2)",
+		"Active quests:\n1. Rats: Kill 2 rat",
+		"You have no quest renamed.",
+	}, notifications)
+}
@@ -0,0 +1,117 @@
+package mud
+
+import (
+	"fmt"
+)
+
+// ListShop shows the items for sale in the shop of the room
+func (p *Player) ListShop() {
+	shopkeeper := p.shopkeeper()
+	if shopkeeper == nil {
+		return
+	}
+
+	p.Notify(fmt.Sprintf("%s sells:\n%s", capitalize(shopkeeper.DefiniteName()), shopkeeper.Shop.Show(p)))
+}
+
+// Buy buys an item from the shop of the room
+func (p *Player) Buy(keyword string) {
+	if p.tooGhostly() {
+		return
+	}
+	shopkeeper := p.shopkeeper()
+	if shopkeeper == nil {
+		return
+	}
+
+	shop := shopkeeper.Shop
+	item := shop.items.Find(keyword)
+	if item == nil {
+		p.Notify(fmt.Sprintf("%s says: I do not have any %s for sale.", capitalize(shopkeeper.DefiniteName()), keyword))
+		return
+	}
+
+	price := shop.SellPrice(item, p)
+	if price > p.Gold {
+		p.Notify(fmt.Sprintf("%s says: %s costs %d gold, and you only have %d.", capitalize(shopkeeper.DefiniteName()), capitalize(item.Name), price, p.Gold))
+		return
+	}
+
+	if !p.CanCarry(item) {
+		p.Notify(fmt.Sprintf("%s is too heavy for you to carry.", capitalize(item.Name)))
+		return
+	}
+
+	p.Gold -= price
+	shop.items = shop.items.Remove(item)
+	p.Inventory = append(p.Inventory, item)
+	p.Notify(fmt.Sprintf("You buy %s for %d gold.", item.Name, price))
+	p.CurrentRoom.Act(p, fmt.Sprintf("%s buys %s.", p.Name, item.Name))
+}
+
+// Sell sells an item from the inventory to the shop of the room
+func (p *Player) Sell(keyword string) {
+	if p.tooGhostly() {
+		return
+	}
+	shopkeeper := p.shopkeeper()
+	if shopkeeper == nil {
+		return
+	}
+
+	item := p.Inventory.Find(keyword)
+	if item == nil {
+		p.Notify(fmt.Sprintf("You do not have any %s.", keyword))
+		return
+	}
+
+	shop := shopkeeper.Shop
+	price := shop.BuyPrice(item, p)
+	if price <= 0 {
+		p.Notify(fmt.Sprintf("%s says: %s is worthless to me.", capitalize(shopkeeper.DefiniteName()), capitalize(item.Name)))
+		return
+	}
+
+	p.Gold += price
+	p.Inventory = p.Inventory.Remove(item)
+	shop.items = append(shop.items, item)
+	p.Notify(fmt.Sprintf("You sell %s for %d gold.", item.Name, price))
+	p.CurrentRoom.Act(p, fmt.Sprintf("%s sells %s.", p.Name, item.Name))
+}
+
+// Value asks the shop of the room how much it would pay for an item of the inventory
+func (p *Player) Value(keyword string) {
+	shopkeeper := p.shopkeeper()
+	if shopkeeper == nil {
+		return
+	}
+
+	item := p.Inventory.Find(keyword)
+	if item == nil {
+		p.Notify(fmt.Sprintf("You do not have any %s.", keyword))
+		return
+	}
+
+	price := shopkeeper.Shop.BuyPrice(item, p)
+	if price <= 0 {
+		p.Notify(fmt.Sprintf("%s says: %s is worthless to me.", capitalize(shopkeeper.DefiniteName()), capitalize(item.Name)))
+		return
+	}
+	p.Notify(fmt.Sprintf("%s says: I would give you %d gold for %s.", capitalize(shopkeeper.DefiniteName()), price, item.Name))
+}
+
+// shopkeeper returns the first NPC of the room with a shop, notifying the player if there is none or the player cannot trade
+func (p *Player) shopkeeper() *NPC {
+	if p.IsSleeping() {
+		p.Notify("You cannot trade while sleeping.")
+		return nil
+	}
+
+	for _, n := range p.CurrentRoom.NPCs {
+		if n.Shop != nil {
+			return n
+		}
+	}
+	p.Notify("There is no shop here.")
+	return nil
+}
@@ -0,0 +1,76 @@
+package mud
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// SpellResurrect brings a ghost in the same room back to life
+	SpellResurrect = "resurrect"
+)
+
+// spellCosts is how much mana each spell takes
+var spellCosts = map[string]int{
+	SpellResurrect: 30,
+}
+
+// Cast casts a spell on the target. Only the spells among the skills of the race or class of the player can be cast
+func (p *Player) Cast(spell, target string) {
+	if p.IsSleeping() {
+		p.Notify("You mumble some words in your sleep, but nothing happens.")
+		return
+	}
+	if !p.knowsSpells() {
+		p.Notify("You do not know how to cast spells.")
+		return
+	}
+	if p.tooGhostly() {
+		return
+	}
+
+	spell = strings.ToLower(spell)
+	if !p.HasSkill(spell) {
+		p.Notify(fmt.Sprintf("You do not know any spell called %s.", spell))
+		return
+	}
+	switch spell {
+	case SpellResurrect:
+		p.castResurrect(target)
+	default:
+		p.Notify(fmt.Sprintf("You do not know any spell called %s.", spell))
+	}
+}
+
+// knowsSpells returns whether the race or class of the player grants any spell
+func (p *Player) knowsSpells() bool {
+	for spell := range spellCosts {
+		if p.HasSkill(spell) {
+			return true
+		}
+	}
+	return false
+}
+
+// castResurrect brings the ghost of another player in the room back to life
+func (p *Player) castResurrect(target string) {
+	if target == "" {
+		p.Notify("Whom do you want to resurrect?")
+		return
+	}
+	ghost := p.CurrentRoom.GetPlayer(target)
+	if ghost == nil || !p.CanSeePlayer(ghost) {
+		p.Notify(fmt.Sprintf("There is no %s here.", target))
+		return
+	}
+	if !ghost.IsGhost {
+		p.Notify(fmt.Sprintf("%s is alive and well.", ghost.Name))
+		return
+	}
+	if !p.spendMana(spellCosts[SpellResurrect]) {
+		return
+	}
+
+	p.Notify(fmt.Sprintf("You chant the words of life over the ghost of %s.", ghost.Name))
+	ghost.Resurrect(p.Name)
+}
@@ -0,0 +1,52 @@
+package mud
+
+import (
+	"fmt"
+)
+
+// Talk starts a conversation with an NPC in the room
+func (p *Player) Talk(keyword string) {
+	npc := p.talkTarget(keyword)
+	if npc == nil {
+		return
+	}
+	p.CurrentRoom.Act(p, fmt.Sprintf("%s talks to %s.", p.Name, npc.DefiniteName()))
+	npc.Talk(p)
+	p.questTalk(npc)
+}
+
+// Ask asks an NPC in the room about the subject
+func (p *Player) Ask(keyword, subject string) {
+	npc := p.talkTarget(keyword)
+	if npc == nil {
+		return
+	}
+	p.CurrentRoom.Act(p, fmt.Sprintf("%s asks %s about %s.", p.Name, npc.DefiniteName(), subject))
+	npc.Ask(p, subject)
+	p.questTalk(npc)
+}
+
+// questTalk advances the quests of the player that require talking or delivering something to the NPC
+func (p *Player) questTalk(npc *NPC) {
+	p.QuestEvent(ObjectiveTalk, npc.FullID())
+	p.QuestEvent(ObjectiveDeliver, npc.FullID())
+}
+
+// talkTarget returns the NPC the player wants to talk to, notifying the player if it cannot be done
+func (p *Player) talkTarget(keyword string) *NPC {
+	if p.IsSleeping() {
+		p.Notify("You mumble something in your dreams.")
+		return nil
+	}
+
+	npc := p.CurrentRoom.GetNPC(keyword)
+	if npc == nil {
+		if mob := p.CurrentRoom.GetMob(keyword, p.CanSeeHidden(), p.CanSeeInvisible()); mob != nil {
+			p.Notify(fmt.Sprintf("%s does not seem to understand you.", capitalize(mob.DefiniteName())))
+			return nil
+		}
+		p.Notify(fmt.Sprintf("There is no %s here to talk to.", keyword))
+		return nil
+	}
+	return npc
+}
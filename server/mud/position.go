@@ -0,0 +1,163 @@
+package mud
+
+import (
+	"fmt"
+)
+
+// Position denotes the posture of a player, which governs the commands allowed and how fast health is recovered
+type Position int
+
+const (
+	// Standing players can do anything. It is the default position
+	Standing Position = iota
+	// Sitting players recover a bit faster, but have to stand up to move or fight
+	Sitting
+	// Resting players recover faster, but have to stand up to move or fight
+	Resting
+	// Sleeping players recover the fastest, but do not see what happens around them and are easy prey for aggressive mobs
+	Sleeping
+	// Fighting players are in the middle of a battle
+	Fighting
+	// Stunned players were caught asleep by an attack, and lose their next turn in the battle unable to dodge
+	Stunned
+)
+
+const (
+	// BaseRegenPercent is the percentage of the maximum health, mana and movement points a standing player recovers on each regen
+	BaseRegenPercent = 10
+	// RoomRegenMultiplier is how many times faster players recover in inns and temples
+	RoomRegenMultiplier = 3
+)
+
+// positionNames maps each position to the name saved with the player
+var positionNames = map[Position]string{
+	Standing: "standing",
+	Sitting:  "sitting",
+	Resting:  "resting",
+	Sleeping: "sleeping",
+	Fighting: "fighting",
+	Stunned:  "stunned",
+}
+
+// positionRegen is the percentage of the base regen players recover on each position
+var positionRegen = map[Position]int{
+	Standing: 100,
+	Sitting:  150,
+	Resting:  200,
+	Sleeping: 300,
+	Fighting: 50,
+	Stunned:  0,
+}
+
+// MarshalText marshals the position into its name
+func (pos Position) MarshalText() ([]byte, error) {
+	name, ok := positionNames[pos]
+	if !ok {
+		return nil, fmt.Errorf("unknown position %d", pos)
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText unmarshals the position from its name
+func (pos *Position) UnmarshalText(b []byte) error {
+	for k, v := range positionNames {
+		if v == string(b) {
+			*pos = k
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown position %s", string(b))
+}
+
+// IsSleeping returns whether the player is asleep, not receiving most of the messages of the game
+func (p *Player) IsSleeping() bool {
+	return p.Position == Sleeping
+}
+
+// IsFighting returns whether the player is in a battle
+func (p *Player) IsFighting() bool {
+	return p.Position == Fighting || p.Position == Stunned
+}
+
+// IsAvailable returns whether the player is standing and free to follow or assist others
+func (p *Player) IsAvailable() bool {
+	return p.Position == Standing && !p.IsGhost
+}
+
+// notStanding returns whether the player has to stand up before moving or fighting, notifying the player if so
+func (p *Player) notStanding() bool {
+	switch p.Position {
+	case Sitting, Resting:
+		p.Notify("You have to stand up first.")
+	case Sleeping:
+		p.Notify("You have to wake up first.")
+	case Stunned:
+		p.Notify("You are still too stunned to do that.")
+	default:
+		return false
+	}
+	return true
+}
+
+// Sit makes the player sit down
+func (p *Player) Sit() {
+	p.changePosition(Sitting, "You sit down.", "%s sits down.")
+}
+
+// Rest makes the player lie down to rest
+func (p *Player) Rest() {
+	p.changePosition(Resting, "You lie down and rest.", "%s lies down to rest.")
+}
+
+// Stand makes the player stand up
+func (p *Player) Stand() {
+	p.changePosition(Standing, "You stand up.", "%s stands up.")
+}
+
+// Sleep puts the player to sleep.
+func (p *Player) Sleep() {
+	p.changePosition(Sleeping, "You lay down and start to sleep.", "%s lies down and falls asleep.")
+}
+
+// Wake wakes up the player.
+func (p *Player) Wake() {
+	if !p.IsSleeping() {
+		p.Notify("You are already awake.")
+		return
+	}
+	p.changePosition(Standing, "You wake up and stand up.", "%s wakes up and stands up.")
+}
+
+// catchAsleep wakes up the player attacked while sleeping, who is left stunned for the next turn of the battle
+func (p *Player) catchAsleep() {
+	if !p.IsSleeping() {
+		return
+	}
+	p.Position = Stunned
+	p.Notify("You wake up with a start, too stunned to defend yourself!")
+}
+
+// changePosition moves the player to a calm position, notifying the player and the room
+func (p *Player) changePosition(to Position, message, act string) {
+	switch {
+	case p.IsFighting():
+		p.Notify("Now is not the time for that, you are in the middle of a fight!")
+		return
+	case p.Position == to:
+		p.Notify(fmt.Sprintf("You are already %s.", positionNames[to]))
+		return
+	}
+
+	p.Position = to
+	p.Notify(message)
+	p.CurrentRoom.Act(p, fmt.Sprintf(act, p.Name))
+}
+
+// regen returns how many points out of the maximum the player recovers on each regen, depending on the position and the room
+func (p *Player) regen(maximum int) int {
+	toRegen := max(1, maximum*BaseRegenPercent/100) * positionRegen[p.Position] / 100
+	if p.CurrentRoom.HasFlag(RoomFlagInn) || p.CurrentRoom.HasFlag(RoomFlagTemple) {
+		toRegen = toRegen * RoomRegenMultiplier
+	}
+	return toRegen
+}
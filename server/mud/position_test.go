@@ -0,0 +1,51 @@
+package mud
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlayerRegen(t *testing.T) {
+	street := &Room{}
+	inn := &Room{Flags: []string{RoomFlagInn}}
+	temple := &Room{Flags: []string{RoomFlagTemple}}
+	tests := []struct {
+		name     string
+		position Position
+		room     *Room
+		expected int
+	}{
+		{"standing", Standing, street, 10},
+		{"sitting", Sitting, street, 15},
+		{"resting", Resting, street, 20},
+		{"sleeping", Sleeping, street, 30},
+		{"fighting", Fighting, street, 5},
+		{"stunned", Stunned, street, 0},
+		{"resting at an inn", Resting, inn, 60},
+		{"sleeping at a temple", Sleeping, temple, 90},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Player{MaxHP: 100, Position: tt.position, CurrentRoom: tt.room}
+			assert.Equal(t, tt.expected, p.regen(p.MaxHP))
+		})
+	}
+}
+
+func TestAggressCatchesSleepers(t *testing.T) {
+	room := &Room{Players: map[string]*Player{}}
+	attacked := []string{}
+	for _, name := range []string{"alice", "bob", "carol"} {
+		p := &Player{UserID: name, Name: name, CurrentRoom: room, Notify: func(string) {}}
+		p.CreateBattle = func(m *Mob) { attacked = append(attacked, p.Name) }
+		room.Players[name] = p
+	}
+	room.Players["bob"].Position = Sleeping
+	m := &Mob{Appearance: Appearance{Name: "wolf"}, CurrentRoom: room}
+
+	assert.True(t, m.Aggress())
+	assert.Equal(t, []string{"bob"}, attacked)
+	assert.Equal(t, Stunned, room.Players["bob"].Position)
+	assert.Equal(t, MinStat, room.Players["bob"].GetCombatStat(Dexterity))
+}
@@ -0,0 +1,244 @@
+package mud
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ObjectiveKill is completed by killing Count mobs with ID MobID
+	ObjectiveKill = "kill"
+	// ObjectiveReach is completed by entering the room Room
+	ObjectiveReach = "reach"
+	// ObjectiveDeliver is completed by talking to the NPC NPC while carrying the item ItemID, which is handed over
+	ObjectiveDeliver = "deliver"
+	// ObjectiveTalk is completed by talking to the NPC NPC
+	ObjectiveTalk = "talk"
+)
+
+// knownObjectives lists all the objectives a quest may have
+var knownObjectives = map[string]bool{
+	ObjectiveKill:    true,
+	ObjectiveReach:   true,
+	ObjectiveDeliver: true,
+	ObjectiveTalk:    true,
+}
+
+// Quest is a goal given to the players, made of objectives completed in order
+type Quest struct {
+	// ID is the unique identifier of the quest
+	ID string `json:"id"`
+	// Name is the title of the quest shown to the player
+	Name string `json:"name"`
+	// Description tells the player what the quest is about
+	Description string `json:"description"`
+	// Objectives lists what the player has to do, in order
+	Objectives []*Objective `json:"objectives"`
+	// Reward is what the player gets when all the objectives are completed
+	Reward Reward `json:"reward"`
+}
+
+// Objective is a single step of a quest
+type Objective struct {
+	// Type is the kind of objective: kill, reach, deliver or talk
+	Type string `json:"type"`
+	// Description is shown to the player on the quest information. Example: Kill 3 bunnies in the forest
+	Description string `json:"description"`
+	// MobID is the mob to kill (kill)
+	MobID string `json:"mob_id"`
+	// Count is how many mobs to kill. Defaults to 1 (kill)
+	Count int `json:"count"`
+	// Room is the full ID of the room to reach, made of the area ID, _ and the room ID. Example: forest_entrance (reach)
+	Room string `json:"room"`
+	// ItemID is the item to deliver (deliver)
+	ItemID string `json:"item_id"`
+	// NPC is the full ID of the NPC to talk or deliver to, made of the area ID, _ and the NPC ID. Example: midgaard_captain (deliver, talk)
+	NPC string `json:"npc"`
+}
+
+// Reward is what a player gets when completing a quest
+type Reward struct {
+	// Experience is how many experience points are given
+	Experience int `json:"experience"`
+	// Gold is how much gold is given
+	Gold int `json:"gold"`
+	// Items lists the IDs of the items given
+	Items []string `json:"items"`
+	// items are the templates of the items given
+	items []*Item
+}
+
+// PlayerQuest stores the progress of a player on a quest
+type PlayerQuest struct {
+	// Step is the index of the objective the player is working on
+	Step int
+	// Count is how many mobs the player killed for the current objective
+	Count int
+	// Completed denotes that all the objectives were completed and the reward was given
+	Completed bool
+	// quest is the quest the progress belongs to, or nil if the quest does not exist anymore
+	quest *Quest
+}
+
+// LoadQuests loads all the quests defined on the JSON files
+func (w *World) LoadQuests(bundlePath string) error {
+	questsDB, err := readQuests(bundlePath, w.mobsDB, w.itemsDB)
+	if err != nil {
+		return err
+	}
+
+	w.questsDB = questsDB
+	return nil
+}
+
+// readQuests reads the quests defined on the JSON files without adding them to the world, linking them to the mobs and items
+func readQuests(bundlePath string, mobs map[string]*Mob, items map[string]*Item) (map[string]*Quest, error) {
+	questsPath := filepath.Join(bundlePath, "assets", "quests")
+	questsDB := make(map[string]*Quest)
+	err := filepath.Walk(questsPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		var quests []*Quest
+		decoder := json.NewDecoder(file)
+		decoder.DisallowUnknownFields()
+		if err = decoder.Decode(&quests); err != nil {
+			return errors.Wrapf(err, "cannot decode %s", path)
+		}
+
+		for _, q := range quests {
+			if _, ok := questsDB[q.ID]; ok {
+				return fmt.Errorf("quest ID %s duplicated", q.ID)
+			}
+			if err = resolveQuest(q, mobs, items); err != nil {
+				return err
+			}
+			questsDB[q.ID] = q
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return questsDB, nil
+}
+
+// resolveQuest checks the quest and links it to the mobs and items
+func resolveQuest(q *Quest, mobs map[string]*Mob, items map[string]*Item) error {
+	if q.Name == "" {
+		q.Name = q.ID
+	}
+	if len(q.Objectives) == 0 {
+		return fmt.Errorf("quest %s has no objectives", q.ID)
+	}
+
+	for _, o := range q.Objectives {
+		switch o.Type {
+		case ObjectiveKill:
+			if _, ok := mobs[o.MobID]; !ok {
+				return fmt.Errorf("cannot find mob with id %s for quest %s", o.MobID, q.ID)
+			}
+			if o.Count <= 0 {
+				o.Count = 1
+			}
+		case ObjectiveDeliver:
+			if _, ok := items[o.ItemID]; !ok {
+				return fmt.Errorf("cannot find item with id %s for quest %s", o.ItemID, q.ID)
+			}
+		case ObjectiveReach, ObjectiveTalk:
+		default:
+			return fmt.Errorf("unknown objective %s for quest %s", o.Type, q.ID)
+		}
+	}
+
+	q.Reward.items = []*Item{}
+	for _, id := range q.Reward.Items {
+		item, ok := items[id]
+		if !ok {
+			return fmt.Errorf("cannot find item with id %s rewarded by quest %s", id, q.ID)
+		}
+		q.Reward.items = append(q.Reward.items, item)
+	}
+	return nil
+}
+
+// Show returns the text shown to the player describing the objective
+func (o *Objective) Show() string {
+	if o.Description != "" {
+		return o.Description
+	}
+	switch o.Type {
+	case ObjectiveKill:
+		return fmt.Sprintf("Kill %d %s", o.Count, o.MobID)
+	case ObjectiveReach:
+		return "Reach " + o.Room
+	case ObjectiveDeliver:
+		return fmt.Sprintf("Deliver %s to %s", o.ItemID, o.NPC)
+	default:
+		return "Talk to " + o.NPC
+	}
+}
+
+// Show returns the rewards as shown to the player
+func (r *Reward) Show() string {
+	rewards := []string{}
+	if r.Experience > 0 {
+		rewards = append(rewards, fmt.Sprintf("%d experience", r.Experience))
+	}
+	if r.Gold > 0 {
+		rewards = append(rewards, fmt.Sprintf("%d gold", r.Gold))
+	}
+	for _, item := range r.items {
+		rewards = append(rewards, item.Name)
+	}
+	if len(rewards) == 0 {
+		return "nothing"
+	}
+	return strings.Join(rewards, ", ")
+}
+
+// Show returns all the information of the quest and the progress of the player
+func (pq *PlayerQuest) Show() string {
+	q := pq.quest
+	status := "active"
+	if pq.Completed {
+		status = "completed"
+	}
+	lines := []string{fmt.Sprintf("%s (%s)", q.Name, status), "", q.Description, "", "Objectives:"}
+	for i, o := range q.Objectives {
+		switch {
+		case pq.Completed || i < pq.Step:
+			lines = append(lines, "* [x] "+o.Show())
+		case i == pq.Step && o.Type == ObjectiveKill:
+			lines = append(lines, fmt.Sprintf("* [ ] %s (%d/%d)", o.Show(), pq.Count, o.Count))
+		case i == pq.Step:
+			lines = append(lines, "* [ ] "+o.Show())
+		}
+	}
+	lines = append(lines, "", "Reward: "+q.Reward.Show())
+	return strings.Join(lines, "\n")
+}
+
+// objective returns the objective the player is working on, or nil if the quest is completed
+func (pq *PlayerQuest) objective() *Objective {
+	if pq.Completed || pq.Step >= len(pq.quest.Objectives) {
+		return nil
+	}
+	return pq.quest.Objectives[pq.Step]
+}
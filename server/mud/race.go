@@ -1,13 +1,61 @@
 package mud
 
-// Race denotes which race a character is (human, elf, dwarf...)
-type Race int
-
-const (
-	// Human are the most equilibrated class, excelling only in Luck
-	Human Race = iota
-	// Elf have more dexterity, intelligence and wisdom, but have little strength or constitution
-	Elf
-	// Dwarf have more strenght, constitution and wisdom, but have little dexterity or intelligence
-	Dwarf
+import (
+	"fmt"
+	"path/filepath"
 )
+
+// DefaultRace is the ID of the race of the new characters
+const DefaultRace = "human"
+
+// legacyRaces are the IDs of the races saved by number, in the order they used to have
+var legacyRaces = []string{"human", "elf", "dwarf"}
+
+// Race denotes which race a character is (human, elf, dwarf...), as defined on assets/races.json
+type Race struct {
+	Traits
+	// CarryBonus is how much more weight the characters can carry, besides their Strength. Negative carries less
+	CarryBonus int `json:"carry_bonus"`
+	// PriceBonus is the percentage the characters haggle from the prices, besides their Luck. Negative pays more
+	PriceBonus int `json:"price_bonus"`
+}
+
+// RaceID is the ID of a race as saved with the players
+type RaceID string
+
+// UnmarshalJSON unmarshals the race ID, translating the races saved by number
+func (id *RaceID) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalLegacyID(b, legacyRaces)
+	*id = RaceID(s)
+	return err
+}
+
+// LoadRaces loads all the races defined on assets/races.json
+func (w *World) LoadRaces(bundlePath string) error {
+	var races []*Race
+	if err := decodeAssetFile(filepath.Join(bundlePath, "assets", "races.json"), &races); err != nil {
+		return err
+	}
+
+	racesDB := make(map[string]*Race)
+	for _, r := range races {
+		if _, ok := racesDB[r.ID]; ok {
+			return fmt.Errorf("race ID %s duplicated", r.ID)
+		}
+		racesDB[r.ID] = r
+	}
+	if racesDB[DefaultRace] == nil {
+		return fmt.Errorf("default race %s not defined", DefaultRace)
+	}
+
+	w.racesDB = racesDB
+	return nil
+}
+
+// race returns the race with the ID, or the default race if there is none
+func (w *World) race(id RaceID) *Race {
+	if r, ok := w.racesDB[string(id)]; ok {
+		return r
+	}
+	return w.racesDB[DefaultRace]
+}
@@ -0,0 +1,157 @@
+package mud
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// templates are the affixes, items, mobs and quests the areas are built from
+type templates struct {
+	affixes []*Affix
+	items   map[string]*Item
+	mobs    map[string]*Mob
+	quests  map[string]*Quest
+}
+
+// currentTemplates returns the templates the world is using
+func (w *World) currentTemplates() *templates {
+	return &templates{affixes: w.affixesDB, items: w.itemsDB, mobs: w.mobsDB, quests: w.questsDB}
+}
+
+// readTemplates reads all the templates from the plugin bundle, without changing the world until the areas are built with them
+func (w *World) readTemplates(bundlePath string) (*templates, error) {
+	affixes, err := readAffixes(bundlePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't load affixes")
+	}
+
+	items, err := readItems(bundlePath, affixes)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't load items")
+	}
+
+	mobs, err := w.readMobs(bundlePath, items)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't load mobs")
+	}
+
+	quests, err := readQuests(bundlePath, mobs, items)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't load quests")
+	}
+
+	return &templates{affixes: affixes, items: items, mobs: mobs, quests: quests}, nil
+}
+
+// ReloadAreas reloads the affixes, the items, the mobs, the quests and the areas with the given IDs from the plugin bundle.
+// If no ID is given, all the areas on the bundle are reloaded. The world is only modified if everything is valid.
+// Returns the IDs of the reloaded areas.
+func (w *World) ReloadAreas(areaIDs []string) ([]string, error) {
+	bundlePath, err := w.api.GetBundlePath()
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't get bundle path")
+	}
+
+	t, err := w.readTemplates(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonAreas, err := readAreaFiles(bundlePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't read areas")
+	}
+
+	if len(areaIDs) > 0 {
+		byID := make(map[string]*JSONArea)
+		for _, a := range jsonAreas {
+			byID[a.ID] = a
+		}
+		jsonAreas = []*JSONArea{}
+		for _, id := range areaIDs {
+			a, ok := byID[id]
+			if !ok {
+				return nil, fmt.Errorf("cannot find area %s", id)
+			}
+			jsonAreas = append(jsonAreas, a)
+		}
+	}
+
+	if err = w.buildAreas(jsonAreas, t); err != nil {
+		return nil, err
+	}
+	return reloadedIDs(jsonAreas), nil
+}
+
+// ReloadAreaFiles reloads the areas from the content of area files, like the ones uploaded by an administrator.
+// Returns the IDs of the reloaded areas.
+func (w *World) ReloadAreaFiles(files map[string][]byte) ([]string, error) {
+	jsonAreas := []*JSONArea{}
+	for name, content := range files {
+		area, err := decodeArea(bytes.NewReader(content))
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot decode %s", name)
+		}
+		jsonAreas = append(jsonAreas, area)
+	}
+
+	if err := w.buildAreas(jsonAreas, w.currentTemplates()); err != nil {
+		return nil, err
+	}
+	return reloadedIDs(jsonAreas), nil
+}
+
+// linkTemplates returns how to point the resets, shops and NPC actions of the areas that are not being replaced to the
+// templates with the same ID, so they stop spawning the old ones. Fails if any of those templates does not exist anymore
+func linkTemplates(areas map[string]*Area, rooms map[string]*Room, replaced map[string]*Area, t *templates) ([]func(), error) {
+	links := []func(){}
+	for id, a := range areas {
+		if _, ok := replaced[id]; ok {
+			continue
+		}
+		for _, r := range a.Resets {
+			resetLinks, err := r.linkTemplates(t, id)
+			if err != nil {
+				return nil, err
+			}
+			links = append(links, resetLinks...)
+		}
+	}
+	for _, room := range rooms {
+		if _, ok := replaced[room.AreaID]; ok {
+			continue
+		}
+		for _, n := range room.NPCs {
+			npcLinks, err := n.linkTemplates(t)
+			if err != nil {
+				return nil, err
+			}
+			links = append(links, npcLinks...)
+		}
+	}
+	return links, nil
+}
+
+// endBattlesIn stops all the battles where anyone is in the given rooms
+func (w *World) endBattlesIn(rooms map[*Room]bool) {
+	remaining := []*Battle{}
+	for _, b := range w.battles {
+		if !b.IsInRooms(rooms) {
+			remaining = append(remaining, b)
+			continue
+		}
+		b.NotifyAll("The battle fades away along with the world around you.")
+		b.end()
+	}
+	w.battles = remaining
+}
+
+func reloadedIDs(jsonAreas []*JSONArea) []string {
+	ids := make([]string, 0, len(jsonAreas))
+	for _, a := range jsonAreas {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
@@ -0,0 +1,64 @@
+package mud
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTownArea = `{
+	"area_id": "town",
+	"recall_room": "square",
+	"flags": ["start"],
+	"rooms": [{"id": "square", "name": "Square", "short_description": "A square."}],
+	"resets": [{"type": "item", "room": "square", "item_id": "bread"}],
+	"npcs": [{"id": "baker", "room": "square", "shop": {"stock": [{"item_id": "bread"}]}}]
+}`
+
+const testFieldArea = `{
+	"area_id": "field",
+	"recall_room": "meadow",
+	"rooms": [{"id": "meadow", "name": "Meadow", "short_description": "A meadow."}]
+}`
+
+func testJSONAreas(t *testing.T, areas ...string) []*JSONArea {
+	jsonAreas := []*JSONArea{}
+	for _, a := range areas {
+		jsonArea, err := decodeArea(strings.NewReader(a))
+		require.NoError(t, err)
+		jsonAreas = append(jsonAreas, jsonArea)
+	}
+	return jsonAreas
+}
+
+func testTemplates(items ...*Item) *templates {
+	t := &templates{items: map[string]*Item{}, mobs: map[string]*Mob{}, quests: map[string]*Quest{}}
+	for _, item := range items {
+		t.items[item.ID] = item
+	}
+	return t
+}
+
+func TestBuildAreasTemplates(t *testing.T) {
+	oldBread := &Item{ID: "bread", Name: "some stale bread"}
+	w := &World{areas: map[string]*Area{}, rooms: map[string]*Room{}}
+	require.NoError(t, w.buildAreas(testJSONAreas(t, testTownArea, testFieldArea), testTemplates(oldBread)))
+	baker := w.rooms["town_square"].NPCs[0]
+
+	t.Run("missing templates leave the world untouched", func(t *testing.T) {
+		err := w.buildAreas(testJSONAreas(t, testFieldArea), testTemplates())
+		assert.EqualError(t, err, "cannot find item with id bread for item reset on area town")
+		assert.Equal(t, oldBread, w.itemsDB["bread"])
+		assert.Equal(t, oldBread, w.areas["town"].Resets[0].item)
+	})
+
+	t.Run("areas not reloaded use the new templates", func(t *testing.T) {
+		newBread := &Item{ID: "bread", Name: "some fresh bread"}
+		require.NoError(t, w.buildAreas(testJSONAreas(t, testFieldArea), testTemplates(newBread)))
+		assert.Equal(t, newBread, w.itemsDB["bread"])
+		assert.Equal(t, newBread, w.areas["town"].Resets[0].item)
+		assert.Equal(t, newBread, baker.Shop.Stock[0].item)
+	})
+}
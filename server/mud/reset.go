@@ -0,0 +1,255 @@
+package mud
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// ResetCheckTime defines how long the reset routine sleeps between checks of the areas
+	ResetCheckTime = 1 * time.Minute
+	// DefaultResetInterval is used for areas that do not define their own reset interval
+	DefaultResetInterval = 10 * time.Minute
+)
+
+const (
+	// ResetMob spawns mobs in a room
+	ResetMob = "mob"
+	// ResetItem loads items on the ground of a room
+	ResetItem = "item"
+	// ResetDoor changes the state of a door
+	ResetDoor = "door"
+	// ResetEquip equips the mobs in a room with an item
+	ResetEquip = "equip"
+	// ResetPut loads items inside the containers lying on the ground of a room
+	ResetPut = "put"
+)
+
+func finishResets() bool {
+	select {
+	case <-worldShutDown:
+		return true
+	default:
+		return false
+	}
+}
+
+// JSONReset is the struct of reset rules on area files of mattermud
+type JSONReset struct {
+	// Type is the kind of rule: mob, item, door, equip or put
+	Type string `json:"type"`
+	// Room is the ID of the room inside the area where the rule applies
+	Room string `json:"room"`
+	// MobID is the mob to spawn (mob) or to equip (equip)
+	MobID string `json:"mob_id"`
+	// ItemID is the item to load on the ground (item), to equip the mob with (equip) or to load inside the containers (put)
+	ItemID string `json:"item_id"`
+	// ContainerID is the item ID of the containers to fill. They are also closed and locked again as their template (put)
+	ContainerID string `json:"container_id"`
+	// Direction is the direction of the door to change (door)
+	Direction string `json:"direction"`
+	// State is the state the door is left in: open, closed or locked (door)
+	State string `json:"state"`
+	// Count is how many mobs or items are created on each reset. Defaults to 1 (mob, item, put)
+	Count int `json:"count"`
+	// Max is the maximum number of those mobs alive, wherever they wandered, or of those items in the room or container. Defaults to Count (mob, item, put)
+	Max int `json:"max"`
+}
+
+// Reset is a single rule run every time the area is reset
+type Reset struct {
+	kind      string
+	room      *Room
+	mob       *Mob
+	item      *Item
+	container *Item
+	direction Direction
+	state     DoorState
+	count     int
+	max       int
+	// spawned lists the mobs created by the rule that may still be alive
+	spawned []*Mob
+}
+
+// jsonResetToReset converts an imported json reset rule into an usable Reset
+func jsonResetToReset(in *JSONReset, areaID string, rooms map[string]*Room, mobs map[string]*Mob, items map[string]*Item) (*Reset, error) {
+	room, ok := rooms[areaID+"_"+in.Room]
+	if !ok {
+		return nil, fmt.Errorf("cannot find room %s for %s reset on area %s", in.Room, in.Type, areaID)
+	}
+
+	out := &Reset{
+		kind:  in.Type,
+		room:  room,
+		count: in.Count,
+		max:   in.Max,
+	}
+	if out.count <= 0 {
+		out.count = 1
+	}
+	if out.max < out.count {
+		out.max = out.count
+	}
+
+	if in.Type == ResetMob || in.Type == ResetEquip {
+		out.mob, ok = mobs[in.MobID]
+		if !ok {
+			return nil, fmt.Errorf("cannot find mob with id %s for %s reset on area %s", in.MobID, in.Type, areaID)
+		}
+	}
+
+	if in.Type == ResetItem || in.Type == ResetEquip || in.Type == ResetPut {
+		out.item, ok = items[in.ItemID]
+		if !ok {
+			return nil, fmt.Errorf("cannot find item with id %s for %s reset on area %s", in.ItemID, in.Type, areaID)
+		}
+	}
+
+	switch in.Type {
+	case ResetMob, ResetItem:
+	case ResetPut:
+		out.container, ok = items[in.ContainerID]
+		if !ok {
+			return nil, fmt.Errorf("cannot find item with id %s for put reset on area %s", in.ContainerID, areaID)
+		}
+		if out.container.Container == nil {
+			return nil, fmt.Errorf("item %s cannot hold items on area %s", in.ContainerID, areaID)
+		}
+	case ResetEquip:
+		if out.item.Equipment == nil {
+			return nil, fmt.Errorf("item %s cannot be equipped on area %s", in.ItemID, areaID)
+		}
+	case ResetDoor:
+		out.direction, ok = DirectionFromString(in.Direction)
+		if !ok {
+			return nil, fmt.Errorf("unknown direction %s for door reset on area %s", in.Direction, areaID)
+		}
+		if room.GetDoor(out.direction) == nil {
+			return nil, fmt.Errorf("no door to the %s of room %s", in.Direction, room.ID)
+		}
+		out.state, ok = doorStateNames[in.State]
+		if !ok {
+			return nil, fmt.Errorf("unknown door state %s on area %s", in.State, areaID)
+		}
+	default:
+		return nil, fmt.Errorf("unknown reset type %s on area %s", in.Type, areaID)
+	}
+
+	return out, nil
+}
+
+// linkTemplates returns how to point the rule to the templates with the same ID. Fails if any of them does not exist anymore
+func (r *Reset) linkTemplates(t *templates, areaID string) ([]func(), error) {
+	mob, item, container := r.mob, r.item, r.container
+	var ok bool
+	if r.mob != nil {
+		if mob, ok = t.mobs[r.mob.ID]; !ok {
+			return nil, fmt.Errorf("cannot find mob with id %s for %s reset on area %s", r.mob.ID, r.kind, areaID)
+		}
+	}
+	if r.item != nil {
+		if item, ok = t.items[r.item.ID]; !ok {
+			return nil, fmt.Errorf("cannot find item with id %s for %s reset on area %s", r.item.ID, r.kind, areaID)
+		}
+		if r.kind == ResetEquip && item.Equipment == nil {
+			return nil, fmt.Errorf("item %s cannot be equipped on area %s", item.ID, areaID)
+		}
+	}
+	if r.container != nil {
+		if container, ok = t.items[r.container.ID]; !ok {
+			return nil, fmt.Errorf("cannot find item with id %s for put reset on area %s", r.container.ID, areaID)
+		}
+		if container.Container == nil {
+			return nil, fmt.Errorf("item %s cannot hold items on area %s", container.ID, areaID)
+		}
+	}
+	return []func(){func() { r.mob, r.item, r.container = mob, item, container }}, nil
+}
+
+// Apply runs the reset rule
+func (r *Reset) Apply() {
+	switch r.kind {
+	case ResetMob:
+		alive := []*Mob{}
+		for _, m := range r.spawned {
+			if m.CurrentHP > 0 && !m.despawned {
+				alive = append(alive, m)
+			}
+		}
+		toSpawn := min(r.count, r.max-len(alive))
+		for i := 0; i < toSpawn; i++ {
+			alive = append(alive, r.room.SpawnMob(r.mob))
+		}
+		r.spawned = alive
+	case ResetItem:
+		toLoad := min(r.count, r.max-r.room.Items.Count(r.item.ID))
+		for i := 0; i < toLoad; i++ {
+			r.room.Items = append(r.room.Items, r.item.Spawn())
+		}
+	case ResetPut:
+		for _, container := range r.room.Items {
+			if container.ID != r.container.ID || container.Container == nil {
+				continue
+			}
+			c := container.Container
+			toLoad := min(r.count, r.max-c.Contents.Count(r.item.ID))
+			for i := 0; i < toLoad && !c.IsFull(); i++ {
+				c.Contents = append(c.Contents, r.item.Spawn())
+			}
+			c.IsClosed = r.container.Container.IsClosed
+			c.IsLocked = r.container.Container.IsLocked
+		}
+	case ResetDoor:
+		r.room.SetDoorState(r.direction, r.state)
+	case ResetEquip:
+		for _, m := range r.room.Mobs {
+			if m.ID != r.mob.ID || m.Equip[r.item.Equipment.Slot] != nil {
+				continue
+			}
+			m.Wear(r.item.Spawn())
+		}
+	}
+}
+
+// Reset runs all the reset rules of the area
+func (a *Area) Reset() {
+	for _, r := range a.Resets {
+		r.Apply()
+	}
+	for _, room := range a.rooms {
+		for _, n := range room.NPCs {
+			if n.Shop != nil {
+				n.Shop.Restock()
+			}
+		}
+	}
+	a.lastReset = time.Now()
+}
+
+// HasPlayers returns whether there is any player in the area
+func (a *Area) HasPlayers() bool {
+	for _, room := range a.rooms {
+		if len(room.Players) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// resetAreas periodically resets the areas without players whose reset interval has passed
+func (w *World) resetAreas() {
+	for {
+		time.Sleep(ResetCheckTime)
+		if finishResets() {
+			return
+		}
+
+		for _, a := range w.areas {
+			if time.Since(a.lastReset) < a.ResetInterval || a.HasPlayers() {
+				continue
+			}
+			a.Reset()
+			w.api.LogDebug("Area reset: " + a.ID)
+		}
+	}
+}
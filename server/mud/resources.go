@@ -0,0 +1,88 @@
+package mud
+
+import (
+	"fmt"
+)
+
+const (
+	// BaseMana is the maximum mana of a player without any Intelligence or Wisdom
+	BaseMana = 20
+	// ManaPerStat is how much maximum mana each point of Intelligence and Wisdom grants
+	ManaPerStat = 5
+	// BaseMoves is the maximum movement points of a player without any Constitution or Dexterity
+	BaseMoves = 50
+	// MovesPerStat is how many maximum movement points each point of Constitution and Dexterity grants
+	MovesPerStat = 5
+)
+
+// MaxMana returns the maximum mana of the player, which grows with Intelligence, Wisdom and the level
+func (p *Player) MaxMana() int {
+	return BaseMana + ManaPerStat*(p.GetCurrentStat(Intelligence)+p.GetCurrentStat(Wisdom)) + p.Level*p.manaPerLevel()
+}
+
+// MaxMoves returns the maximum movement points of the player, which grow with Constitution and Dexterity
+func (p *Player) MaxMoves() int {
+	return BaseMoves + MovesPerStat*(p.GetCurrentStat(Constitution)+p.GetCurrentStat(Dexterity))
+}
+
+// moveCost returns how many movement points it takes the player to walk into the room. Ghosts float for free,
+// and carrying too much doubles the cost.
+func (p *Player) moveCost(to *Room) int {
+	if p.IsGhost {
+		return 0
+	}
+	cost := to.Terrain.MoveCost()
+	if p.IsEncumbered() {
+		cost *= 2
+	}
+	return cost
+}
+
+// tooExhausted returns whether the player lacks the movement points to walk into the room, notifying the player if so
+func (p *Player) tooExhausted(to *Room) bool {
+	if p.CurrentMoves >= p.moveCost(to) {
+		return false
+	}
+	p.Notify("You are too exhausted to go any further. Rest for a while.")
+	return true
+}
+
+// spendMana takes the mana from the player if there is enough, notifying the player otherwise. Returns whether it was spent
+func (p *Player) spendMana(cost int) bool {
+	if p.CurrentMana < cost {
+		p.Notify(fmt.Sprintf("You need %d mana for that, but you only have %d.", cost, p.CurrentMana))
+		return false
+	}
+	p.CurrentMana -= cost
+	return true
+}
+
+// regenerate recovers health, mana and movement points, depending on the position and the room
+func (p *Player) regenerate() {
+	if p.IsGhost {
+		return
+	}
+	p.CurrentHP = min(p.MaxHP, p.CurrentHP+p.regen(p.MaxHP))
+	p.CurrentMana = min(p.MaxMana(), p.CurrentMana+p.regen(p.MaxMana()))
+	p.CurrentMoves = min(p.MaxMoves(), p.CurrentMoves+p.regen(p.MaxMoves()))
+}
+
+// ShowStatus returns the health, mana, movement points and gold of the player
+func (p *Player) ShowStatus() string {
+	return fmt.Sprintf("%d/%d HP, %d/%d mana, %d/%d moves, %d gold", p.CurrentHP, p.MaxHP, p.CurrentMana, p.MaxMana(), p.CurrentMoves, p.MaxMoves(), p.Gold)
+}
+
+// PromptLine returns the line appended to the notifications of the players who turned the prompt on
+func (p *Player) PromptLine() string {
+	return fmt.Sprintf("`%d/%d HP | %d/%d mana | %d/%d moves`", p.CurrentHP, p.MaxHP, p.CurrentMana, p.MaxMana(), p.CurrentMoves, p.MaxMoves())
+}
+
+// SetPrompt sets whether the resources of the player are shown after every notification
+func (p *Player) SetPrompt(on bool) {
+	p.Prompt = on
+	if on {
+		p.Notify("Your health, mana and movement points will be shown after every message.")
+		return
+	}
+	p.Notify("The prompt is now off.")
+}
@@ -0,0 +1,76 @@
+package mud
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlayerResources(t *testing.T) {
+	tests := []struct {
+		name          string
+		player        *Player
+		expectedMana  int
+		expectedMoves int
+	}{
+		{"no stats", &Player{Race: testHuman, Class: testWarrior}, BaseMana, BaseMoves + 5},
+		{"mage", &Player{Race: testHuman, Class: testMage, Stats: Stats{Intelligence: 4, Wisdom: 2}}, BaseMana + 45, BaseMoves},
+		{"weakened", &Player{Race: testHuman, Class: testRogue, Stats: Stats{Dexterity: 4}, Effects: EffectList{{StatsModifiers: Stats{Dexterity: -2}}}}, BaseMana, BaseMoves + 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedMana, tt.player.MaxMana())
+			assert.Equal(t, tt.expectedMoves, tt.player.MaxMoves())
+		})
+	}
+}
+
+func TestMoveCost(t *testing.T) {
+	street := &Room{}
+	woods := &Room{Terrain: Forest}
+	anvil := &Item{Weight: 1000}
+	tests := []struct {
+		name     string
+		player   *Player
+		room     *Room
+		expected int
+	}{
+		{"city", &Player{}, street, 1},
+		{"forest", &Player{}, woods, 3},
+		{"encumbered", &Player{Inventory: ItemList{anvil}}, woods, 6},
+		{"ghost", &Player{IsGhost: true}, woods, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.player.moveCost(tt.room))
+		})
+	}
+}
+
+func TestSavedResources(t *testing.T) {
+	tests := []struct {
+		name          string
+		saved         string
+		expectedMana  int
+		expectedMoves int
+	}{
+		{"saves older than mana start full", `{"MaxHP": 20}`, 30, 80},
+		{"drained resources stay drained", `{"CurrentMana": 0, "CurrentMoves": 0}`, 0, 0},
+		{"partially spent", `{"CurrentMana": 12, "CurrentMoves": 40}`, 12, 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved JSONPlayer
+			require.NoError(t, json.Unmarshal([]byte(tt.saved), &saved))
+			assert.Equal(t, tt.expectedMana, savedOrFull(saved.CurrentMana, 30))
+			assert.Equal(t, tt.expectedMoves, savedOrFull(saved.CurrentMoves, 80))
+		})
+	}
+
+	drained := playerToJSONPlayer(&Player{Race: testHuman, Class: testWarrior, CurrentRoom: &Room{}})
+	b, err := json.Marshal(drained)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"CurrentMana":0,"CurrentMoves":0`)
+}
@@ -26,6 +26,78 @@ const (
 	Down
 )
 
+// directionNames maps the direction names used on the area files to each direction
+var directionNames = map[string]Direction{
+	"north": North,
+	"south": South,
+	"west":  West,
+	"east":  East,
+	"up":    Up,
+	"down":  Down,
+}
+
+// DirectionFromString returns the direction with the name s, as used on the area files
+func DirectionFromString(s string) (Direction, bool) {
+	d, ok := directionNames[s]
+	return d, ok
+}
+
+// directionName returns the name of the direction as used on the area files
+func directionName(d Direction) string {
+	for name, v := range directionNames {
+		if v == d {
+			return name
+		}
+	}
+	return ""
+}
+
+// directionFrom returns how to say where someone comes from when arriving from direction d. Example: the north, below
+func directionFrom(d Direction) string {
+	switch d {
+	case Up:
+		return "above"
+	case Down:
+		return "below"
+	default:
+		return "the " + directionName(d)
+	}
+}
+
+// Opposite returns the direction that leads back to where you came from
+func (d Direction) Opposite() Direction {
+	switch d {
+	case North:
+		return South
+	case South:
+		return North
+	case West:
+		return East
+	case East:
+		return West
+	case Up:
+		return Down
+	default:
+		return Up
+	}
+}
+
+const (
+	// RoomFlagSafe marks the rooms where players can never fight each other
+	RoomFlagSafe = "safe"
+	// RoomFlagTemple marks the rooms where ghosts come back to life
+	RoomFlagTemple = "temple"
+	// RoomFlagInn marks the rooms where players recover faster
+	RoomFlagInn = "inn"
+)
+
+// knownRoomFlags lists all the flags that a room may have
+var knownRoomFlags = map[string]bool{
+	RoomFlagSafe:   true,
+	RoomFlagTemple: true,
+	RoomFlagInn:    true,
+}
+
 // Room stores the information of each room in the game
 type Room struct {
 	// ID is the unique identifier for this room
@@ -40,12 +112,107 @@ type Room struct {
 	LongDescription string
 	// Mobs lists all the mobs present in the room
 	Mobs MobList
+	// NPCs lists all the non-hostile characters standing in the room
+	NPCs []*NPC
+	// Items lists all the items lying on the ground
+	Items ItemList
 	// Player lists all players in the room
 	Players map[string]*Player
 	// Neighbours contains all the neighbour rooms to this one
 	Neighbours map[Direction]*RoomDoor
+	// ExtraDescriptions contains the details of the room that can be looked at
+	ExtraDescriptions []*ExtraDescription
+	// Flags lists all the flags of the room
+	Flags []string
+	// Terrain is the kind of ground of the room, which sets how many movement points it takes to walk into it
+	Terrain Terrain
 	// shouts contains the latest shouts on the area
 	shouts map[string]time.Time
+	// area is the area the room belongs to
+	area *Area
+}
+
+// HasFlag returns whether the room has certain flag
+func (r *Room) HasFlag(flag string) bool {
+	for _, v := range r.Flags {
+		if v == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// IsArena returns whether the room belongs to an arena, where players can always fight each other
+func (r *Room) IsArena() bool {
+	return r.area != nil && r.area.HasFlag(AreaFlagArena)
+}
+
+// ExtraDescription is a detail of a room that players can look at, like a statue or an altar
+type ExtraDescription struct {
+	// Keywords are the words the player can use to look at the detail
+	Keywords []string `json:"keywords"`
+	// Description is shown to the player when looking at the detail
+	Description string `json:"description"`
+}
+
+// Matches returns whether the player may refer to this detail by keyword
+func (e *ExtraDescription) Matches(keyword string) bool {
+	keyword = strings.ToLower(keyword)
+	for _, v := range e.Keywords {
+		if keyword == strings.ToLower(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetExtraDescription returns the first detail of the room matching the keyword, or nil if there is no such detail
+func (r *Room) GetExtraDescription(keyword string) *ExtraDescription {
+	for _, v := range r.ExtraDescriptions {
+		if v.Matches(keyword) {
+			return v
+		}
+	}
+	return nil
+}
+
+// GetNPC returns the first NPC on the room matching the keyword, or nil if there is no such NPC
+func (r *Room) GetNPC(keyword string) *NPC {
+	for _, v := range r.NPCs {
+		if v.Matches(keyword) {
+			return v
+		}
+	}
+	return nil
+}
+
+// GetPlayer returns the first player on the room whose name matches the keyword, or nil if there is no such player
+func (r *Room) GetPlayer(keyword string) *Player {
+	for _, v := range r.Players {
+		if strings.EqualFold(v.Name, keyword) {
+			return v
+		}
+	}
+	return nil
+}
+
+// DoorState denotes whether a door is open, closed or locked
+type DoorState int
+
+const (
+	// DoorOpen denotes an open door, or a transition without door
+	DoorOpen DoorState = iota
+	// DoorClosed denotes a closed door that anyone can open
+	DoorClosed
+	// DoorLocked denotes a closed door that has to be unlocked first
+	DoorLocked
+)
+
+// doorStateNames maps the door state names used on the area files to each state
+var doorStateNames = map[string]DoorState{
+	"open":   DoorOpen,
+	"closed": DoorClosed,
+	"locked": DoorLocked,
 }
 
 // RoomDoor stores information about the transition between a room and the next
@@ -54,6 +221,10 @@ type RoomDoor struct {
 	isHidden bool
 	// isInvisible denotes whether the transition is magically invisible
 	isInvisible bool
+	// isDoor denotes whether the transition has a door that can be opened and closed
+	isDoor bool
+	// isClosed denotes whether the door is closed
+	isClosed bool
 	// isLocked denotes whether the transition has a locked door
 	isLocked bool
 	// key denotes which key is needed to unlock the door. Empty string would mean the door can be unlocked without any key.
@@ -69,7 +240,7 @@ func (r *Room) CanMove(d Direction, canSeeHidden, canSeeInvisible bool) bool {
 		return false
 	}
 
-	if door.isLocked {
+	if door.isClosed {
 		return false
 	}
 
@@ -89,7 +260,7 @@ func (r *Room) GetNeighbourRoom(d Direction) *Room {
 	return r.Neighbours[d].room
 }
 
-// CanSeeDoor return whether a locked door can be seen in direction d
+// CanSeeDoor return whether a door can be seen in direction d
 func (r *Room) CanSeeDoor(d Direction, canSeeHidden, canSeeInvisible bool) bool {
 	door, ok := r.Neighbours[d]
 	if !ok {
@@ -104,11 +275,52 @@ func (r *Room) CanSeeDoor(d Direction, canSeeHidden, canSeeInvisible bool) bool
 		return false
 	}
 
-	if !door.isLocked {
-		return false
+	return door.isDoor
+}
+
+// Key returns the ID of the item needed to unlock the door
+func (door *RoomDoor) Key() string {
+	return door.key
+}
+
+// GetDoor returns the door in direction d, or nil if there is no door
+func (r *Room) GetDoor(d Direction) *RoomDoor {
+	door, ok := r.Neighbours[d]
+	if !ok || !door.isDoor {
+		return nil
 	}
+	return door
+}
 
-	return true
+// State returns whether the door is open, closed or locked
+func (door *RoomDoor) State() DoorState {
+	switch {
+	case door.isLocked:
+		return DoorLocked
+	case door.isClosed:
+		return DoorClosed
+	default:
+		return DoorOpen
+	}
+}
+
+// SetDoorState changes the state of the door in direction d, and of the door on the other side if any
+func (r *Room) SetDoorState(d Direction, state DoorState) {
+	door := r.GetDoor(d)
+	if door == nil {
+		return
+	}
+	door.setState(state)
+
+	back := door.room.GetDoor(d.Opposite())
+	if back != nil && back.room == r {
+		back.setState(state)
+	}
+}
+
+func (door *RoomDoor) setState(state DoorState) {
+	door.isClosed = state != DoorOpen
+	door.isLocked = state == DoorLocked
 }
 
 // Show returns all the visible information of the room
@@ -134,6 +346,15 @@ func (r *Room) Show(userID string, canSeeHidden, canSeeInvisible, isLooking bool
 		message += fmt.Sprintf("\n\n%s", strings.Join(playersList, "\n"))
 	}
 
+	npcsList := []string{}
+	for _, n := range r.NPCs {
+		npcsList = append(npcsList, n.Show())
+	}
+
+	if len(npcsList) > 0 {
+		message += fmt.Sprintf("\n\n%s", strings.Join(npcsList, "\n"))
+	}
+
 	mobsList := []string{}
 	for _, m := range r.Mobs {
 		if m.CurrentHP <= 0 {
@@ -151,16 +372,31 @@ func (r *Room) Show(userID string, canSeeHidden, canSeeInvisible, isLooking bool
 		message += fmt.Sprintf("\n\n%s", strings.Join(mobsList, "\n"))
 	}
 
+	itemsList := []string{}
+	for _, i := range r.Items {
+		itemsList = append(itemsList, i.Show())
+	}
+
+	if len(itemsList) > 0 {
+		message += fmt.Sprintf("\n\n%s", strings.Join(itemsList, "\n"))
+	}
+
 	return message
 }
 
 // Enter deals with the logic of a player entering a room when moving on direction d.
-// The logic includes adding the user to the players list and notifying the other present players.
+// The logic includes adding the user to the players list, notifying the other present players and being attacked by aggressive mobs.
 func (r *Room) Enter(p *Player, d Direction) {
 	for _, player := range r.Players {
 		player.NotifyEnteringPlayer(p, d)
 	}
 	r.Players[p.UserID] = p
+
+	for _, m := range append(MobList{}, r.Mobs...) {
+		if m.HasFlag(MobFlagAggressive) && m.CurrentHP > 0 && m.CanSeePlayer(p) {
+			m.Attack(p)
+		}
+	}
 }
 
 // Exit deals with the logic of a player exiting a room when moving on direction d.
@@ -210,10 +446,60 @@ func (r *Room) shoutEcho(userID, shoutID, userName, message string, isHidden, is
 	}
 }
 
-// GetMob gets the first mob with ID mobID and returns it. Returns nil if not such mob.
-func (r *Room) GetMob(mobID string) *Mob {
+// Announce notifies all the awake players in the room
+func (r *Room) Announce(message string) {
+	for _, player := range r.Players {
+		if player.IsSleeping() {
+			continue
+		}
+		player.Notify(message)
+	}
+}
+
+// Act notifies the other awake players in the room that can see the player about something the player did
+func (r *Room) Act(p *Player, message string) {
+	for _, player := range r.Players {
+		if player == p || player.IsSleeping() || !player.CanSeePlayer(p) {
+			continue
+		}
+		player.Notify(message)
+	}
+}
+
+// ActMob notifies the awake players in the room that can see the mob about something the mob did
+func (r *Room) ActMob(m *Mob, message string) {
+	for _, player := range r.Players {
+		if player.IsSleeping() || !player.CanSeeMob(m) {
+			continue
+		}
+		player.Notify(message)
+	}
+}
+
+// SpawnMob creates a new mob in the room using another mob as template
+func (r *Room) SpawnMob(template *Mob) *Mob {
+	mob := template.Spawn(r)
+	r.Mobs = append(r.Mobs, mob)
+	return mob
+}
+
+// RemoveMob removes the mob from the room
+func (r *Room) RemoveMob(mob *Mob) {
+	for i, v := range r.Mobs {
+		if v == mob {
+			r.Mobs = append(r.Mobs[:i], r.Mobs[i+1:]...)
+			return
+		}
+	}
+}
+
+// GetMob gets the first alive mob matching the keyword that can be seen and returns it. Returns nil if not such mob.
+func (r *Room) GetMob(keyword string, canSeeHidden, canSeeInvisible bool) *Mob {
 	for _, v := range r.Mobs {
-		if v.ID == mobID && v.CurrentHP > 0 {
+		if (!canSeeHidden && v.IsHidden()) || (!canSeeInvisible && v.IsInvisible()) {
+			continue
+		}
+		if v.Matches(keyword) && v.CurrentHP > 0 {
 			return v
 		}
 	}
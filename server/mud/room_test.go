@@ -0,0 +1,54 @@
+package mud
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMob(t *testing.T) {
+	invisible := &Mob{ID: "ghost_guard", Appearance: Appearance{Name: "ghostly guard", Keywords: []string{"guard"}}, CurrentHP: 10, Effects: EffectList{{GrantInvisible: true}}}
+	hidden := &Mob{ID: "lurking_guard", Appearance: Appearance{Name: "lurking guard", Keywords: []string{"guard"}}, CurrentHP: 10, Effects: EffectList{{GrantHidden: true}}}
+	visible := &Mob{ID: "city_guard", Appearance: Appearance{Name: "city guard", Keywords: []string{"guard"}}, CurrentHP: 10}
+	room := &Room{Mobs: MobList{invisible, hidden, visible}}
+
+	tests := []struct {
+		name            string
+		canSeeHidden    bool
+		canSeeInvisible bool
+		expected        *Mob
+	}{
+		{"skips the mobs that cannot be seen", false, false, visible},
+		{"sees hidden", true, false, hidden},
+		{"sees invisible", false, true, invisible},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, room.GetMob("guard", tt.canSeeHidden, tt.canSeeInvisible))
+		})
+	}
+}
+
+func TestNotifyMovingPlayer(t *testing.T) {
+	tests := []struct {
+		direction Direction
+		exiting   string
+		entering  string
+	}{
+		{North, "bob leaves north.", "bob arrives from the south."},
+		{East, "bob leaves east.", "bob arrives from the west."},
+		{Up, "bob leaves up.", "bob arrives from below."},
+		{Down, "bob leaves down.", "bob arrives from above."},
+	}
+	for _, tt := range tests {
+		t.Run(directionName(tt.direction), func(t *testing.T) {
+			notifications := []string{}
+			alice := &Player{Name: "alice", Notify: func(message string) { notifications = append(notifications, message) }}
+			bob := &Player{Name: "bob"}
+
+			alice.NotifyExitingPlayer(bob, tt.direction)
+			alice.NotifyEnteringPlayer(bob, tt.direction)
+			assert.Equal(t, []string{tt.exiting, tt.entering}, notifications)
+		})
+	}
+}
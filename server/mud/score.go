@@ -0,0 +1,65 @@
+package mud
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ShowScore shows the character sheet of the player
+func (p *Player) ShowScore() {
+	p.Notify(p.score(time.Now()))
+}
+
+// score returns the character sheet of the player as Markdown tables: the character, the stats with and without
+// modifiers, the active effects and the equipment
+func (p *Player) score(now time.Time) string {
+	lines := []string{
+		fmt.Sprintf("| Character | %s |", p.Name),
+		"|:----------|:---|",
+		fmt.Sprintf("| Race | %s |", p.Race),
+		fmt.Sprintf("| Class | %s |", p.Class),
+		fmt.Sprintf("| Level | %d |", p.Level),
+		fmt.Sprintf("| Experience | %d (%d to next level) |", p.Experience, ExperienceForLevel(p.Level+1)-p.Experience),
+		fmt.Sprintf("| HP | %d/%d |", p.CurrentHP, p.MaxHP),
+		fmt.Sprintf("| Mana | %d/%d |", p.CurrentMana, p.MaxMana()),
+		fmt.Sprintf("| Moves | %d/%d |", p.CurrentMoves, p.MaxMoves()),
+		fmt.Sprintf("| Attack | %d right, %d left |", p.GetRightAttack(), p.GetLeftAttack()),
+		fmt.Sprintf("| Defense | %d |", p.GetCurrentDefense()),
+		fmt.Sprintf("| Gold | %d |", p.Gold),
+		fmt.Sprintf("| Position | %s |", positionNames[p.Position]),
+		"",
+		"| Stat | Base | Current |",
+		"|:-----|-----:|--------:|",
+	}
+	for s := Strength; s < StatsLength; s++ {
+		lines = append(lines, fmt.Sprintf("| %s | %d | %d |", s, p.Stats[s], p.GetCurrentStat(s)))
+	}
+
+	lines = append(lines, "", "**Effects:** "+showEffects(p.effects(), now))
+	equipment := p.Equip.Show()
+	if len(equipment) == 0 {
+		return strings.Join(append(lines, "", "**Equipment:** none"), "\n")
+	}
+	return strings.Join(append(lines, "", "**Equipment:**", "* "+strings.Join(equipment, "\n* ")), "\n")
+}
+
+// showEffects returns the abilities granted by the effects and the temporary effects along with how long they last
+func showEffects(effects EffectList, now time.Time) string {
+	names := effects.Names()
+	for _, e := range effects {
+		if e.Name == "" {
+			continue
+		}
+		if e.ExpiresAt.IsZero() {
+			names = append(names, e.Name)
+			continue
+		}
+		minutes := int(e.ExpiresAt.Sub(now).Minutes()) + 1
+		names = append(names, fmt.Sprintf("%s (%d min left)", e.Name, minutes))
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,43 @@
+package mud
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlayerScore(t *testing.T) {
+	now := time.Now()
+	sword := &Item{Name: "a rusty sword", Equipment: &Equipment{Slot: RightHand, Attack: 3}}
+	p := &Player{
+		Name:         "alice",
+		Race:         testDwarf,
+		Class:        testWarrior,
+		Level:        1,
+		Experience:   150,
+		MaxHP:        110,
+		CurrentHP:    80,
+		CurrentMana:  10,
+		CurrentMoves: 60,
+		Gold:         25,
+		Stats:        Stats{Strength: 5},
+		Equip:        PlayerEquipment{RightHand: sword},
+		Effects: EffectList{
+			{SeeHidden: true},
+			{Name: "weakness", StatsModifiers: Stats{Strength: -2}, ExpiresAt: now.Add(4*time.Minute + 30*time.Second)},
+		},
+	}
+
+	score := p.score(now)
+	assert.Contains(t, score, "| Character | alice |")
+	assert.Contains(t, score, "| Race | Dwarf |")
+	assert.Contains(t, score, "| Class | Warrior |")
+	assert.Contains(t, score, "| Experience | 150 (250 to next level) |")
+	assert.Contains(t, score, "| HP | 80/110 |")
+	assert.Contains(t, score, "| Attack | 10 right, 0 left |")
+	assert.Contains(t, score, "| Strength | 5 | 7 |")
+	assert.Contains(t, score, "| Dexterity | 0 | 0 |")
+	assert.Contains(t, score, "**Effects:** see hidden, weakness (5 min left)")
+	assert.Contains(t, score, "* right hand: a rusty sword")
+}
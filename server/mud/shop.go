@@ -0,0 +1,110 @@
+package mud
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultSellRate is the percentage of the item value that shops charge when they do not define their own
+	DefaultSellRate = 100
+	// DefaultBuyRate is the percentage of the item value that shops pay when they do not define their own
+	DefaultBuyRate = 50
+	// MaxPriceBonus is the maximum percentage a player can haggle from the prices
+	MaxPriceBonus = 20
+)
+
+// Shop is the stock and prices of a shopkeeper NPC
+type Shop struct {
+	// Stock lists the items the shop has available after each reset
+	Stock []*ShopStock `json:"stock"`
+	// SellRate is the percentage of the item value the shop charges. Defaults to 100
+	SellRate int `json:"sell_rate"`
+	// BuyRate is the percentage of the item value the shop pays for the items players sell. Defaults to 50
+	BuyRate int `json:"buy_rate"`
+	// items lists the items currently for sale
+	items ItemList
+}
+
+// ShopStock is an item the shop has available after each reset
+type ShopStock struct {
+	// ItemID is the item for sale
+	ItemID string `json:"item_id"`
+	// Count is how many of them are available after each reset. Defaults to 1
+	Count int `json:"count"`
+	// item is the template of the item for sale
+	item *Item
+}
+
+// resolve checks the shop and links it to the item templates
+func (s *Shop) resolve(npcID, areaID string, items map[string]*Item) error {
+	if s.SellRate == 0 {
+		s.SellRate = DefaultSellRate
+	}
+	if s.BuyRate == 0 {
+		s.BuyRate = DefaultBuyRate
+	}
+	if s.SellRate < 0 || s.BuyRate < 0 {
+		return fmt.Errorf("negative rates for the shop of npc %s on area %s", npcID, areaID)
+	}
+
+	for _, v := range s.Stock {
+		item, ok := items[v.ItemID]
+		if !ok {
+			return fmt.Errorf("cannot find item with id %s for the shop of npc %s on area %s", v.ItemID, npcID, areaID)
+		}
+		v.item = item
+		if v.Count <= 0 {
+			v.Count = 1
+		}
+	}
+	return nil
+}
+
+// Restock adds the items missing from the stock
+func (s *Shop) Restock() {
+	for _, v := range s.Stock {
+		for i := s.items.Count(v.ItemID); i < v.Count; i++ {
+			s.items = append(s.items, v.item.Spawn())
+		}
+	}
+}
+
+// SellPrice returns how much the player has to pay for the item
+func (s *Shop) SellPrice(item *Item, p *Player) int {
+	price := item.Value * s.SellRate * (100 - p.PriceBonus()) / 10000
+	return max(price, 1)
+}
+
+// BuyPrice returns how much the shop pays the player for the item. It is never higher than the sell price.
+func (s *Shop) BuyPrice(item *Item, p *Player) int {
+	price := item.Value * s.BuyRate * (100 + p.PriceBonus()) / 10000
+	return min(price, s.SellPrice(item, p))
+}
+
+// Show returns the list of items for sale with their prices for the player
+func (s *Shop) Show(p *Player) string {
+	if len(s.items) == 0 {
+		return "There is nothing for sale right now."
+	}
+
+	lines := []string{}
+	counted := make(map[string]bool)
+	for _, item := range s.items {
+		if counted[item.ID] {
+			continue
+		}
+		counted[item.ID] = true
+		lines = append(lines, fmt.Sprintf("* %s: %d gold (%d left)", item.Name, s.SellPrice(item, p), s.items.Count(item.ID)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PriceBonus returns the percentage the player haggles from the prices, given by Luck and race
+func (p *Player) PriceBonus() int {
+	bonus := p.GetCurrentStat(Luck)
+	if p.Race != nil {
+		bonus += p.Race.PriceBonus
+	}
+	return max(-MaxPriceBonus, min(bonus, MaxPriceBonus))
+}
@@ -1,6 +1,7 @@
 package mud
 
 import (
+	"bytes"
 	"encoding/json"
 )
 
@@ -27,14 +28,41 @@ const (
 	StatsLength
 )
 
+const (
+	// MinStat is the lowest value a stat can have after all the modifiers
+	MinStat = 0
+	// MaxStat is the highest value a stat can have after all the modifiers
+	MaxStat = 30
+)
+
+// statNames maps each stat to the name shown to the player
+var statNames = map[Stat]string{
+	Strength:     "Strength",
+	Constitution: "Constitution",
+	Dexterity:    "Dexterity",
+	Intelligence: "Intelligence",
+	Wisdom:       "Wisdom",
+	Luck:         "Luck",
+}
+
+// String returns the name of the stat
+func (s Stat) String() string {
+	return statNames[s]
+}
+
+// clampStat keeps the value of a stat between MinStat and MaxStat
+func clampStat(value int) int {
+	return max(MinStat, min(value, MaxStat))
+}
+
 // StatsJSON represents the stats in JSON format
 type StatsJSON struct {
-	Strength     int
-	Constitution int
-	Dexterity    int
-	Intelligence int
-	Wisdom       int
-	Luck         int
+	Strength     int `json:"strength"`
+	Constitution int `json:"constitution"`
+	Dexterity    int `json:"dexterity"`
+	Intelligence int `json:"intelligence"`
+	Wisdom       int `json:"wisdom"`
+	Luck         int `json:"luck"`
 }
 
 // MarshalJSON marshals the stats into JSON
@@ -49,20 +77,22 @@ func (s Stats) MarshalJSON() ([]byte, error) {
 	return json.Marshal(sJSON)
 }
 
-// UnmarshalJSON unmarshal the stats from JSON
-func (s Stats) UnmarshalJSON(b []byte) error {
-	s = make(map[Stat]int)
+// UnmarshalJSON unmarshal the stats from JSON. Unknown stats are rejected.
+func (s *Stats) UnmarshalJSON(b []byte) error {
 	var sJSON StatsJSON
-	err := json.Unmarshal(b, &sJSON)
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&sJSON)
 	if err != nil {
 		return err
 	}
-	s[Strength] = sJSON.Strength
-	s[Constitution] = sJSON.Constitution
-	s[Dexterity] = sJSON.Dexterity
-	s[Intelligence] = sJSON.Intelligence
-	s[Wisdom] = sJSON.Wisdom
-	s[Luck] = sJSON.Luck
+	*s = make(map[Stat]int)
+	(*s)[Strength] = sJSON.Strength
+	(*s)[Constitution] = sJSON.Constitution
+	(*s)[Dexterity] = sJSON.Dexterity
+	(*s)[Intelligence] = sJSON.Intelligence
+	(*s)[Wisdom] = sJSON.Wisdom
+	(*s)[Luck] = sJSON.Luck
 
 	return nil
 }
@@ -0,0 +1,117 @@
+package mud
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlayerGetCurrentStat(t *testing.T) {
+	helmet := &Item{Equipment: &Equipment{Slot: Head, StatsModifiers: Stats{Strength: 2}}}
+	curse := &Item{Equipment: &Equipment{Slot: Necklace, StatsModifiers: Stats{Strength: -10}}}
+	tests := []struct {
+		name     string
+		player   *Player
+		stat     Stat
+		expected int
+	}{
+		{"base only", &Player{Race: testHuman, Class: testMage, Stats: Stats{Strength: 5}}, Strength, 5},
+		{"race bonus", &Player{Race: testDwarf, Class: testMage, Stats: Stats{Strength: 5}}, Strength, 7},
+		{"class bonus", &Player{Race: testHuman, Class: testWarrior, Stats: Stats{Strength: 5}}, Strength, 7},
+		{"race and class bonuses", &Player{Race: testDwarf, Class: testWarrior, Stats: Stats{Strength: 5}}, Strength, 9},
+		{"race penalty", &Player{Race: testElf, Class: testMage, Stats: Stats{Strength: 5}}, Strength, 4},
+		{"equipment", &Player{Race: testHuman, Class: testMage, Stats: Stats{Strength: 5}, Equip: PlayerEquipment{Head: helmet}}, Strength, 7},
+		{"effects", &Player{Race: testHuman, Class: testMage, Stats: Stats{Strength: 5}, Effects: EffectList{{StatsModifiers: Stats{Strength: 3}}}}, Strength, 8},
+		{"everything", &Player{Race: testDwarf, Class: testWarrior, Stats: Stats{Strength: 5}, Equip: PlayerEquipment{Head: helmet}, Effects: EffectList{{StatsModifiers: Stats{Strength: 3}}}}, Strength, 14},
+		{"floored", &Player{Race: testHuman, Class: testMage, Stats: Stats{Strength: 5}, Equip: PlayerEquipment{Necklace: curse}}, Strength, MinStat},
+		{"capped", &Player{Race: testDwarf, Class: testWarrior, Stats: Stats{Strength: MaxStat}}, Strength, MaxStat},
+		{"other stats are not affected", &Player{Race: testDwarf, Class: testWarrior, Stats: Stats{Strength: 5}, Equip: PlayerEquipment{Head: helmet}}, Intelligence, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.player.GetCurrentStat(tt.stat))
+		})
+	}
+}
+
+func TestPlayerAttacks(t *testing.T) {
+	sword := &Item{Name: "a rusty sword", Equipment: &Equipment{Slot: RightHand, Attack: 3}}
+	dagger := &Item{Name: "a dagger", Equipment: &Equipment{Slot: LeftHand, Attack: 2}}
+	shield := &Item{Name: "a wooden shield", Equipment: &Equipment{Slot: LeftHand}}
+	ring := &Item{Name: "a ring", Equipment: &Equipment{Slot: RightRing, Attack: 1}}
+	tests := []struct {
+		name          string
+		player        *Player
+		expectedRight int
+		expectedLeft  int
+		expected      []Attack
+	}{
+		{
+			"bare hands",
+			&Player{Class: testMage, Stats: Stats{Strength: 2}},
+			0, 0,
+			[]Attack{{Power: UnarmedAttack + 2}},
+		},
+		{
+			"right hand weapon",
+			&Player{Class: testMage, Stats: Stats{Strength: 2}, Equip: PlayerEquipment{RightHand: sword}},
+			5, 0,
+			[]Attack{{Power: 5, Weapon: sword}},
+		},
+		{
+			"weapon on each hand",
+			&Player{Class: testMage, Stats: Stats{Strength: 2}, Equip: PlayerEquipment{RightHand: sword, LeftHand: dagger}},
+			5, 4,
+			[]Attack{{Power: 5, Weapon: sword}, {Power: 4, Weapon: dagger}},
+		},
+		{
+			"shields do not attack",
+			&Player{Class: testMage, Stats: Stats{Strength: 2}, Equip: PlayerEquipment{RightHand: sword, LeftHand: shield}},
+			5, 0,
+			[]Attack{{Power: 5, Weapon: sword}},
+		},
+		{
+			"modifiers of other equipment and effects",
+			&Player{Class: testMage, Stats: Stats{Strength: 2}, Equip: PlayerEquipment{RightHand: sword, RightRing: ring}, Effects: EffectList{{Attack: 2}}},
+			8, 0,
+			[]Attack{{Power: 8, Weapon: sword}},
+		},
+		{
+			"floored",
+			&Player{Class: testMage, Equip: PlayerEquipment{RightHand: sword}, Effects: EffectList{{Attack: -10}}},
+			0, 0,
+			[]Attack{{Power: 0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedRight, tt.player.GetRightAttack())
+			assert.Equal(t, tt.expectedLeft, tt.player.GetLeftAttack())
+			assert.Equal(t, tt.expected, tt.player.Attacks())
+		})
+	}
+}
+
+func TestMobStats(t *testing.T) {
+	club := &Item{Equipment: &Equipment{Slot: RightHand, Attack: 4, StatsModifiers: Stats{Dexterity: -1}}}
+	tests := []struct {
+		name              string
+		mob               *Mob
+		expectedStrength  int
+		expectedDexterity int
+		expectedAttack    int
+	}{
+		{"base stats", &Mob{Stats: Stats{Strength: 3, Dexterity: 2}}, 3, 2, 3},
+		{"equipment", &Mob{Stats: Stats{Strength: 3, Dexterity: 2}, Equip: PlayerEquipment{RightHand: club}}, 3, 1, 7},
+		{"effects", &Mob{Stats: Stats{Strength: 3, Dexterity: 2}, Effects: EffectList{{Attack: 1, StatsModifiers: Stats{Strength: 2}}}}, 5, 2, 6},
+		{"floored", &Mob{Stats: Stats{Strength: 1}, Effects: EffectList{{Attack: -5, StatsModifiers: Stats{Strength: -5, Dexterity: -5}}}}, MinStat, MinStat, 0},
+		{"capped", &Mob{Stats: Stats{Strength: 50, Dexterity: 40}}, MaxStat, MaxStat, MaxStat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedStrength, tt.mob.GetCurrentStat(Strength))
+			assert.Equal(t, tt.expectedDexterity, tt.mob.GetCurrentStat(Dexterity))
+			assert.Equal(t, tt.expectedAttack, tt.mob.GetAttack())
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package mud
+
+import (
+	"fmt"
+)
+
+// Terrain denotes the kind of ground of a room, which sets how many movement points it takes to walk into it
+type Terrain int
+
+const (
+	// City streets and buildings are the easiest to walk. It is the default terrain
+	City Terrain = iota
+	// Field covers plains, meadows and farmland
+	Field
+	// Forest covers woods and jungles
+	Forest
+	// Hills covers hills and rough ground
+	Hills
+	// Mountain covers mountains and cliffs
+	Mountain
+	// Water covers shallow water that can be waded through
+	Water
+)
+
+// terrainNames maps each terrain to the name used on the JSON files
+var terrainNames = map[Terrain]string{
+	City:     "city",
+	Field:    "field",
+	Forest:   "forest",
+	Hills:    "hills",
+	Mountain: "mountain",
+	Water:    "water",
+}
+
+// terrainMoveCost is how many movement points it takes to walk into a room of each terrain
+var terrainMoveCost = map[Terrain]int{
+	City:     1,
+	Field:    2,
+	Forest:   3,
+	Hills:    4,
+	Mountain: 6,
+	Water:    5,
+}
+
+// MarshalText marshals the terrain into its name
+func (t Terrain) MarshalText() ([]byte, error) {
+	name, ok := terrainNames[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown terrain %d", t)
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText unmarshals the terrain from its name
+func (t *Terrain) UnmarshalText(b []byte) error {
+	for k, v := range terrainNames {
+		if v == string(b) {
+			*t = k
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown terrain %s", string(b))
+}
+
+// MoveCost returns how many movement points it takes to walk into a room of the terrain
+func (t Terrain) MoveCost() int {
+	return terrainMoveCost[t]
+}
@@ -0,0 +1,161 @@
+package mud
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Traits are what a race or a class grants to the characters belonging to it
+type Traits struct {
+	// ID is the identifier saved with the players. Changing it breaks the existing characters
+	ID string `json:"id"`
+	// Name is how the race or class is shown to the players, like Elf or Mage
+	Name string `json:"name"`
+	// Description explains the race or class to the players
+	Description string `json:"description"`
+	// StatsModifiers denotes how much the race or class modifies each stat
+	StatsModifiers Stats `json:"stats_modifiers"`
+	// HPPerLevel is how many maximum health points the characters gain on each level
+	HPPerLevel int `json:"hp_per_level"`
+	// ManaPerLevel is how much maximum mana the characters gain on each level
+	ManaPerLevel int `json:"mana_per_level"`
+	// AllowedSlots lists the equipment slots the characters can use. Empty means all of them
+	AllowedSlots []EquipmentSlot `json:"allowed_slots"`
+	// Effects are the innate effects of the characters, like the elves seeing invisible things
+	Effects EffectList `json:"effects"`
+	// Skills lists the spells the characters know, like resurrect
+	Skills []string `json:"skills"`
+}
+
+// String returns the name of the race or class
+func (t *Traits) String() string {
+	return t.Name
+}
+
+// allowsSlot returns whether the race or class lets the characters use the equipment slot
+func (t *Traits) allowsSlot(slot EquipmentSlot) bool {
+	if len(t.AllowedSlots) == 0 {
+		return true
+	}
+	for _, s := range t.AllowedSlots {
+		if s == slot {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSkill returns whether the race or class grants the skill
+func (t *Traits) hasSkill(skill string) bool {
+	for _, s := range t.Skills {
+		if s == skill {
+			return true
+		}
+	}
+	return false
+}
+
+// traits returns the traits of the race and the class of the player, skipping the ones not set
+func (p *Player) traits() []*Traits {
+	traits := []*Traits{}
+	if p.Race != nil {
+		traits = append(traits, &p.Race.Traits)
+	}
+	if p.Class != nil {
+		traits = append(traits, &p.Class.Traits)
+	}
+	return traits
+}
+
+// effects returns the innate effects of the race and the class of the player, followed by the effects the player is under
+func (p *Player) effects() EffectList {
+	effects := EffectList{}
+	for _, t := range p.traits() {
+		effects = append(effects, t.Effects...)
+	}
+	return append(effects, p.Effects...)
+}
+
+// HasSkill returns whether the race or the class of the player grants the skill
+func (p *Player) HasSkill(skill string) bool {
+	for _, t := range p.traits() {
+		if t.hasSkill(skill) {
+			return true
+		}
+	}
+	return false
+}
+
+// forbiddingSlot returns the race or class that does not let the player use the equipment slot, or nil if both allow it
+func (p *Player) forbiddingSlot(slot EquipmentSlot) *Traits {
+	for _, t := range p.traits() {
+		if !t.allowsSlot(slot) {
+			return t
+		}
+	}
+	return nil
+}
+
+// traitsStatModifiers returns how much the race and the class of the player modify the stat
+func (p *Player) traitsStatModifiers(s Stat) int {
+	modifier := 0
+	for _, t := range p.traits() {
+		modifier += t.StatsModifiers[s]
+	}
+	return modifier
+}
+
+// hpPerLevel returns how many maximum health points the player gains on each level
+func (p *Player) hpPerLevel() int {
+	hp := 0
+	for _, t := range p.traits() {
+		hp += t.HPPerLevel
+	}
+	return hp
+}
+
+// manaPerLevel returns how much maximum mana the player gains on each level
+func (p *Player) manaPerLevel() int {
+	mana := 0
+	for _, t := range p.traits() {
+		mana += t.ManaPerLevel
+	}
+	return mana
+}
+
+// decodeAssetFile decodes a JSON asset file into v, rejecting unknown fields
+func decodeAssetFile(path string, v interface{}) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(v); err != nil {
+		return errors.Wrapf(err, "cannot decode %s", path)
+	}
+	return nil
+}
+
+// unmarshalLegacyID unmarshals the ID of a race or class saved with a player. Saves older than the race and class
+// files stored the position on the old list instead, which is translated through legacyIDs
+func unmarshalLegacyID(b []byte, legacyIDs []string) (string, error) {
+	var id string
+	if err := json.Unmarshal(b, &id); err == nil {
+		return id, nil
+	}
+	position, err := strconv.Atoi(string(b))
+	if err != nil {
+		return "", fmt.Errorf("invalid ID %s", string(b))
+	}
+	if position < 0 || position >= len(legacyIDs) {
+		return "", fmt.Errorf("unknown legacy ID %d", position)
+	}
+	return legacyIDs[position], nil
+}
@@ -0,0 +1,105 @@
+package mud
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testHuman   = &Race{Traits: Traits{ID: "human", Name: "Human", StatsModifiers: Stats{Luck: 1}}}
+	testElf     = &Race{Traits: Traits{ID: "elf", Name: "Elf", StatsModifiers: Stats{Strength: -1, Constitution: -1, Dexterity: 2, Intelligence: 1, Wisdom: 1}, Effects: EffectList{{Name: "elven sight", SeeInvisible: true}}}, CarryBonus: -5}
+	testDwarf   = &Race{Traits: Traits{ID: "dwarf", Name: "Dwarf", StatsModifiers: Stats{Strength: 2, Constitution: 2, Dexterity: -1, Intelligence: -1, Wisdom: 1}, HPPerLevel: 2}, CarryBonus: 10}
+	testWarrior = &Class{Traits: Traits{ID: "warrior", Name: "Warrior", StatsModifiers: Stats{Strength: 2, Constitution: 1}, HPPerLevel: 12}}
+	testMage    = &Class{Traits: Traits{ID: "mage", Name: "Mage", StatsModifiers: Stats{Intelligence: 2, Wisdom: 1}, HPPerLevel: 6, ManaPerLevel: 5, AllowedSlots: []EquipmentSlot{Head, RightHand}, Skills: []string{SpellResurrect}}}
+	testRogue   = &Class{Traits: Traits{ID: "rogue", Name: "Rogue", StatsModifiers: Stats{Dexterity: 2, Luck: 1}, HPPerLevel: 9}}
+)
+
+func TestLoadRacesAndClasses(t *testing.T) {
+	w := &World{}
+	require.NoError(t, w.LoadRaces("../.."))
+	require.NoError(t, w.LoadClasses("../.."))
+
+	assert.True(t, w.racesDB["elf"].Effects.CanSeeInvisible())
+	assert.Equal(t, 10, w.racesDB["dwarf"].CarryBonus)
+	assert.True(t, w.classesDB["mage"].hasSkill(SpellResurrect))
+	assert.Equal(t, w.racesDB[DefaultRace], w.race("gnome"))
+	assert.Equal(t, w.classesDB[DefaultClass], w.class(""))
+}
+
+func TestUnmarshalSavedTraits(t *testing.T) {
+	tests := []struct {
+		name          string
+		saved         string
+		expectedRace  RaceID
+		expectedClass ClassID
+		expectedError bool
+	}{
+		{"string IDs", `{"Race": "dwarf", "Class": "rogue"}`, "dwarf", "rogue", false},
+		{"legacy numbers", `{"Race": 1, "Class": 1}`, "elf", "mage", false},
+		{"new race unknown to old saves", `{"Race": "gnome", "Class": 0}`, "gnome", "warrior", false},
+		{"legacy number out of range", `{"Race": 7, "Class": 0}`, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved JSONPlayer
+			err := json.Unmarshal([]byte(tt.saved), &saved)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRace, saved.Race)
+			assert.Equal(t, tt.expectedClass, saved.Class)
+		})
+	}
+}
+
+func TestPlayerTraits(t *testing.T) {
+	elfMage := &Player{Race: testElf, Class: testMage, Level: 2, Effects: EffectList{{SeeHidden: true}}}
+	dwarfWarrior := &Player{Race: testDwarf, Class: testWarrior}
+
+	assert.True(t, elfMage.CanSeeInvisible())
+	assert.True(t, elfMage.CanSeeHidden())
+	assert.False(t, dwarfWarrior.CanSeeInvisible())
+
+	assert.True(t, elfMage.HasSkill(SpellResurrect))
+	assert.False(t, dwarfWarrior.HasSkill(SpellResurrect))
+
+	assert.Equal(t, BaseMana+ManaPerStat*(3+2)+2*5, elfMage.MaxMana())
+	assert.Equal(t, 14, dwarfWarrior.hpPerLevel())
+	assert.Equal(t, BaseCarryWeight+CarryWeightPerStrength*4+10, dwarfWarrior.CarryCapacity())
+}
+
+func TestPlayerWear(t *testing.T) {
+	sword := &Item{ID: "sword", Name: "a sword", Keywords: []string{"sword"}, Equipment: &Equipment{Slot: RightHand}}
+	dagger := &Item{ID: "dagger", Name: "a dagger", Keywords: []string{"dagger"}, Equipment: &Equipment{Slot: RightHand}}
+	bread := &Item{ID: "bread", Name: "some bread", Keywords: []string{"bread"}}
+
+	tests := []struct {
+		name              string
+		class             *Class
+		expectedEquip     PlayerEquipment
+		expectedInventory ItemList
+	}{
+		{"dual wield", testRogue, PlayerEquipment{RightHand: sword, LeftHand: dagger}, ItemList{bread}},
+		{"left hand not allowed", testMage, PlayerEquipment{RightHand: dagger}, ItemList{bread, sword}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Player{Class: tt.class, Inventory: ItemList{sword, dagger, bread}, CurrentRoom: &Room{}}
+			p.Notify = func(string) {}
+			p.Wear("sword")
+			p.Wear("dagger")
+			p.Wear("bread")
+			assert.Equal(t, tt.expectedEquip, p.Equip)
+			assert.Equal(t, tt.expectedInventory, p.Inventory)
+
+			p.Remove("dagger")
+			assert.Nil(t, p.Equip[LeftHand])
+			assert.Contains(t, p.Inventory, dagger)
+		})
+	}
+}
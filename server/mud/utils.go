@@ -22,43 +22,71 @@ func max(a, b int) int {
 	return b
 }
 
-// jsonRoomsToRooms convert imported json rooms to usable Rooms in the game
-func jsonRoomsToRooms(in map[string]*JSONRoom, mobs map[string]*Mob) (map[string]*Room, error) {
+// capitalize returns the string with the first letter in upper case
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// indefiniteArticle returns "an" for words starting with a vowel and "a" for the rest
+func indefiniteArticle(word string) string {
+	if word != "" && strings.ContainsAny(strings.ToLower(word[:1]), "aeiou") {
+		return "an"
+	}
+	return "a"
+}
+
+// healthCondition returns how hurt someone looks given their current and maximum health points
+func healthCondition(currentHP, maxHP int) string {
+	switch percent := currentHP * 100 / max(maxHP, 1); {
+	case percent >= 100:
+		return "is in perfect health"
+	case percent >= 75:
+		return "has a few scratches"
+	case percent >= 50:
+		return "has some wounds"
+	case percent >= 25:
+		return "is badly hurt"
+	default:
+		return "is about to die"
+	}
+}
+
+// jsonRoomsToRooms convert imported json rooms to usable Rooms in the game.
+// Neighbours not found among the imported rooms are looked for in the external rooms.
+func jsonRoomsToRooms(in map[string]*JSONRoom, external map[string]*Room) (map[string]*Room, error) {
 	out := make(map[string]*Room)
 
 	for k, v := range in {
+		for _, flag := range v.Flags {
+			if !knownRoomFlags[flag] {
+				return nil, fmt.Errorf("unknown flag %s for room %s", flag, k)
+			}
+		}
 		out[k] = &Room{
-			ID:               v.ID,
-			Name:             v.Name,
-			AreaID:           v.AreaID,
-			ShortDescription: v.ShortDescription,
-			LongDescription:  v.LongDescription,
-			Mobs:             MobList{},
-			Players:          make(map[string]*Player),
-			Neighbours:       make(map[Direction]*RoomDoor),
-			shouts:           make(map[string]time.Time),
+			ID:                v.ID,
+			Name:              v.Name,
+			AreaID:            v.AreaID,
+			ShortDescription:  v.ShortDescription,
+			LongDescription:   v.LongDescription,
+			ExtraDescriptions: v.ExtraDescriptions,
+			Flags:             v.Flags,
+			Terrain:           v.Terrain,
+			Mobs:              MobList{},
+			Items:             ItemList{},
+			Players:           make(map[string]*Player),
+			Neighbours:        make(map[Direction]*RoomDoor),
+			shouts:            make(map[string]time.Time),
 		}
 	}
 
 	for id, room := range in {
-		for _, mobID := range room.Mobs {
-			mobToAdd, ok := mobs[mobID]
-			if !ok {
-				return nil, fmt.Errorf("cannot find mob with id %s", mobID)
-			}
-			out[id].Mobs = append(out[id].Mobs, mobToAdd.Spawn())
-		}
 		for direction, door := range room.Neighbours {
-			var directionKey Direction
-			switch direction {
-			case "north":
-				directionKey = North
-			case "south":
-				directionKey = South
-			case "east":
-				directionKey = East
-			case "west":
-				directionKey = West
+			directionKey, ok := DirectionFromString(direction)
+			if !ok {
+				return nil, fmt.Errorf("unknown direction %s for room %s", direction, id)
 			}
 
 			roomID := room.AreaID + "_" + door.Room
@@ -67,6 +95,9 @@ func jsonRoomsToRooms(in map[string]*JSONRoom, mobs map[string]*Mob) (map[string
 			}
 
 			neighbourRoom, ok := out[roomID]
+			if !ok {
+				neighbourRoom, ok = external[roomID]
+			}
 			if !ok {
 				return nil, fmt.Errorf("cannot find neighbour with id %s for room %s", roomID, id)
 			}
@@ -74,7 +105,10 @@ func jsonRoomsToRooms(in map[string]*JSONRoom, mobs map[string]*Mob) (map[string
 			out[id].Neighbours[directionKey] = &RoomDoor{
 				isHidden:    door.IsHidden,
 				isInvisible: door.IsInvisible,
+				isDoor:      door.IsDoor || door.IsClosed || door.IsLocked,
+				isClosed:    door.IsClosed || door.IsLocked,
 				isLocked:    door.IsLocked,
+				key:         door.KeyID,
 				room:        neighbourRoom,
 			}
 		}
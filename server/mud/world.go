@@ -3,8 +3,11 @@ package mud
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"math/rand"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/mattermost/mattermost-server/v5/model"
 	"github.com/mattermost/mattermost-server/v5/plugin"
@@ -13,12 +16,27 @@ import (
 
 var worldShutDown = make(chan struct{})
 
+// Config contains the world settings that can be changed by the administrators
+type Config struct {
+	// DefaultRoom is the ID of the room where all new players start. If empty, the recall room of the area flagged as start is used
+	DefaultRoom string
+	// Death are the consequences of dying
+	Death DeathPenalties
+}
+
 // World stores all the information from the game
 type World struct {
 	api       plugin.API
 	botUserID string
+	config    Config
+	areas     map[string]*Area
 	rooms     map[string]*Room
 	mobsDB    map[string]*Mob
+	itemsDB   map[string]*Item
+	questsDB  map[string]*Quest
+	racesDB   map[string]*Race
+	classesDB map[string]*Class
+	affixesDB []*Affix
 	players   map[string]*Player
 	battles   []*Battle
 	// defaultRoom is the room where all new players start, and where players end up if there is any problem with the rooms
@@ -27,26 +45,51 @@ type World struct {
 
 // JSONArea is the struct of area files of mattermud
 type JSONArea struct {
-	ID    string      `json:"area_id"`
+	// ID is the unique ID of the area. It is used as prefix for the IDs of all its rooms
+	ID string `json:"area_id"`
+	// Name is the name of the area shown to the player
+	Name string `json:"name"`
+	// Author is who wrote the area
+	Author string `json:"author"`
+	// MinLevel is the lowest recommended level to visit the area. 0 means no minimum
+	MinLevel int `json:"min_level"`
+	// MaxLevel is the highest recommended level to visit the area. 0 means no maximum
+	MaxLevel int `json:"max_level"`
+	// RecallRoom is the ID of the room inside the area where players are sent when recalling
+	RecallRoom string `json:"recall_room"`
+	// ResetInterval is how many minutes pass between resets of the area
+	ResetInterval int `json:"reset_interval"`
+	// Flags lists special properties of the area. The area flagged as start provides the default room of the world, and arenas let players always fight each other
+	Flags []string `json:"flags"`
+	// Rooms is the list of rooms in the area
 	Rooms []*JSONRoom `json:"rooms"`
+	// Resets is the list of rules run every time the area is reset
+	Resets []*JSONReset `json:"resets"`
+	// NPCs is the list of non-hostile characters of the area
+	NPCs []*NPC `json:"npcs"`
 }
 
 // JSONRoom is the struct of rooms on area files of mattermud
 type JSONRoom struct {
 	// AreaID is the unique ID for the area. Not present on each room on the json file
-	AreaID string
+	AreaID string `json:"-"`
 	// ID is the unique ID of the room inside the area. Final ID will be AreaID + _ + ID
 	ID string `json:"id"`
 	// Name is the name shown to the player
 	Name string `json:"name"`
-	// ShortDescription is the name shown to the player
+	// ShortDescription is the description shown to the player when entering the room
 	ShortDescription string `json:"short_description"`
-	// LongDescription is the name shown to the player when using the command look
+	// LongDescription is the description shown to the player when using the command look
 	LongDescription string `json:"long_description"`
-	// Mobs is the list of IDs of Mobs in the area
-	Mobs []string `json:"mobs"`
-	// Neighbours is map of rooms neighbour to this one
+	// Neighbours is map of rooms neighbour to this one, keyed by direction (north, south, east, west, up, down)
 	Neighbours map[string]JSONNeighbour `json:"neighbours"`
+	// ExtraDescriptions are the details of the room that can be looked at, like a statue or an altar
+	ExtraDescriptions []*ExtraDescription `json:"extra_descriptions"`
+	// Flags lists the flags of the room. Safe rooms never allow players to fight each other, temples bring ghosts back to life,
+	// and players recover faster in temples and inns
+	Flags []string `json:"flags"`
+	// Terrain is the kind of ground of the room, which sets how many movement points it takes to walk into it. City by default
+	Terrain Terrain `json:"terrain"`
 }
 
 // JSONNeighbour is the struct for room transitions on area files of mattermud
@@ -55,34 +98,84 @@ type JSONNeighbour struct {
 	IsHidden bool `json:"is_hidden"`
 	// IsInvisible shows whether the transition is invisible
 	IsInvisible bool `json:"is_invisible"`
+	// IsDoor shows whether the transition has a door that can be opened and closed
+	IsDoor bool `json:"is_door"`
+	// IsClosed shows whether the door starts closed. Locked doors are always closed
+	IsClosed bool `json:"is_closed"`
 	// IsLocked shows whether the transition is locked behind a door
 	IsLocked bool `json:"is_locked"`
 	// Room is the ID of the room this transition connects to. External transitions will have the __EXT__ prefix
 	Room string `json:"id"`
-	// KeyID is the key needed to open the door
+	// KeyID is the ID of the item needed to unlock the door. Empty means the door can be unlocked without any key
 	KeyID string `json:"key_id"`
 }
 
 // NewWorld creates a new world
-func NewWorld(api plugin.API, botUserID string) World {
+func NewWorld(api plugin.API, botUserID string, config Config) World {
 	return World{
 		api:       api,
 		botUserID: botUserID,
+		config:    config,
+	}
+}
+
+// SetConfig updates the world settings
+func (w *World) SetConfig(config Config) {
+	w.config = config
+	if w.rooms == nil {
+		return
+	}
+
+	defaultRoom, err := w.findDefaultRoom(w.areas, w.rooms)
+	if err != nil {
+		w.api.LogError("cannot update the default room: " + err.Error())
+		return
+	}
+	w.defaultRoom = defaultRoom
+	for _, p := range w.players {
+		p.DefaultRoom = w.rooms[w.defaultRoom]
 	}
 }
 
 // Init initializes the world
 func (w *World) Init() error {
+	rand.Seed(time.Now().UnixNano())
+
 	bundlePath, err := w.api.GetBundlePath()
 	if err != nil {
 		return errors.Wrap(err, "couldn't get bundle path")
 	}
 
+	err = w.LoadRaces(bundlePath)
+	if err != nil {
+		return errors.Wrap(err, "couldn't load races")
+	}
+
+	err = w.LoadClasses(bundlePath)
+	if err != nil {
+		return errors.Wrap(err, "couldn't load classes")
+	}
+
+	err = w.LoadAffixes(bundlePath)
+	if err != nil {
+		return errors.Wrap(err, "couldn't load affixes")
+	}
+
+	err = w.LoadItems(bundlePath)
+	if err != nil {
+		return errors.Wrap(err, "couldn't load items")
+	}
+
 	err = w.LoadMobs(bundlePath)
 	if err != nil {
 		return errors.Wrap(err, "couldn't load mobs")
 	}
 
+	err = w.LoadQuests(bundlePath)
+	if err != nil {
+		return errors.Wrap(err, "couldn't load quests")
+	}
+
 	err = w.LoadRooms(bundlePath)
 	if err != nil {
 		return errors.Wrap(err, "couldn't load rooms")
@@ -99,13 +192,27 @@ func (w *World) Init() error {
 
 	go w.autoSave()
 	go w.garbageCollector()
+	go w.resetAreas()
+	go w.mobsAct()
 	return nil
 }
 
 // LoadRooms loads all the rooms defined on the JSON files
 func (w *World) LoadRooms(bundlePath string) error {
+	jsonAreas, err := readAreaFiles(bundlePath)
+	if err != nil {
+		return errors.WithMessage(err, "OnActivate/LoadRooms failed")
+	}
+
+	w.areas = make(map[string]*Area)
+	w.rooms = make(map[string]*Room)
+	return w.buildAreas(jsonAreas, w.currentTemplates())
+}
+
+// readAreaFiles reads all the area files from the bundle
+func readAreaFiles(bundlePath string) ([]*JSONArea, error) {
 	areasPath := filepath.Join(bundlePath, "assets", "areas")
-	jsonRooms := make(map[string]*JSONRoom)
+	jsonAreas := []*JSONArea{}
 	err := filepath.Walk(areasPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -120,43 +227,71 @@ func (w *World) LoadRooms(bundlePath string) error {
 		}
 		defer file.Close()
 
-		var area JSONArea
-		decoder := json.NewDecoder(file)
-		if err = decoder.Decode(&area); err != nil {
-			return err
-		}
-
-		for _, v := range area.Rooms {
-			v.ID = area.ID + "_" + v.ID
-			v.AreaID = area.ID
-			if _, ok := jsonRooms[v.ID]; ok {
-				return fmt.Errorf("room id '%s' duplicated", v.ID)
-			}
-			jsonRooms[v.ID] = v
+		area, err := decodeArea(file)
+		if err != nil {
+			return errors.Wrapf(err, "cannot decode %s", path)
 		}
+		jsonAreas = append(jsonAreas, area)
 
 		return nil
 	})
 
-	if err != nil {
-		return errors.WithMessage(err, "OnActivate/LoadRooms failed")
+	return jsonAreas, err
+}
+
+// decodeArea decodes an area file, rejecting any unknown field
+func decodeArea(r io.Reader) (*JSONArea, error) {
+	var area JSONArea
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&area); err != nil {
+		return nil, err
 	}
-	w.rooms, err = jsonRoomsToRooms(jsonRooms, w.mobsDB)
-	if err != nil {
-		return err
+	return &area, nil
+}
+
+// findDefaultRoom returns the configured default room, or the recall room of the area flagged as start if none is configured
+func (w *World) findDefaultRoom(areas map[string]*Area, rooms map[string]*Room) (string, error) {
+	if w.config.DefaultRoom != "" {
+		if _, ok := rooms[w.config.DefaultRoom]; ok {
+			return w.config.DefaultRoom, nil
+		}
+		w.api.LogWarn("configured default room not found, using the start area instead", "room", w.config.DefaultRoom)
 	}
 
-	//TODO Use JSON areas to define the default room
-	w.defaultRoom = "midgaard_temple"
+	var startArea *Area
+	for _, a := range areas {
+		if !a.HasFlag(AreaFlagStart) {
+			continue
+		}
+		if startArea != nil {
+			return "", fmt.Errorf("areas %s and %s are both flagged as start", startArea.ID, a.ID)
+		}
+		startArea = a
+	}
+	if startArea == nil {
+		return "", errors.New("no default room configured and no area flagged as start")
+	}
 
-	return nil
+	return startArea.RecallRoom.ID, nil
 }
 
 // LoadMobs loads all mobs defined on the JSON files
 func (w *World) LoadMobs(bundlePath string) error {
+	mobsDB, err := w.readMobs(bundlePath, w.itemsDB)
+	if err != nil {
+		return err
+	}
+
+	w.mobsDB = mobsDB
+	return nil
+}
+
+// readMobs reads the mobs defined on the JSON files without adding them to the world, linking their drops to the items
+func (w *World) readMobs(bundlePath string, items map[string]*Item) (map[string]*Mob, error) {
 	mobsPath := filepath.Join(bundlePath, "assets", "mobs")
 	w.api.LogDebug(mobsPath)
-	w.mobsDB = make(map[string]*Mob)
+	mobsDB := make(map[string]*Mob)
 	err := filepath.Walk(mobsPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -173,22 +308,103 @@ func (w *World) LoadMobs(bundlePath string) error {
 
 		var mobs []*Mob
 		decoder := json.NewDecoder(file)
+		decoder.DisallowUnknownFields()
 		if err = decoder.Decode(&mobs); err != nil {
-			return err
+			return errors.Wrapf(err, "cannot decode %s", path)
 		}
 
 		for _, mob := range mobs {
-			if _, ok := w.mobsDB[mob.ID]; ok {
+			if _, ok := mobsDB[mob.ID]; ok {
 				return fmt.Errorf("Mob ID %s duplicated", mob.ID)
 			}
-			w.mobsDB[mob.ID] = mob
+			if mob.Name == "" {
+				mob.Name = mob.ID
+			}
+			for _, flag := range mob.Flags {
+				if !knownMobFlags[flag] {
+					return fmt.Errorf("unknown flag %s for mob %s", flag, mob.ID)
+				}
+			}
+			for _, d := range mob.Drops {
+				item, ok := items[d.ItemID]
+				if !ok {
+					return fmt.Errorf("cannot find item with id %s dropped by mob %s", d.ItemID, mob.ID)
+				}
+				d.item = item
+			}
+			mobsDB[mob.ID] = mob
 			w.api.LogDebug("Loaded mob " + mob.ID)
 		}
 
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+	return mobsDB, nil
+}
 
-	return err
+// LoadItems loads all items defined on the JSON files
+func (w *World) LoadItems(bundlePath string) error {
+	itemsDB, err := readItems(bundlePath, w.affixesDB)
+	if err != nil {
+		return err
+	}
+
+	w.itemsDB = itemsDB
+	return nil
+}
+
+// readItems reads the items defined on the JSON files without adding them to the world, giving them the affixes they can roll
+func readItems(bundlePath string, affixes []*Affix) (map[string]*Item, error) {
+	itemsPath := filepath.Join(bundlePath, "assets", "items")
+	itemsDB := make(map[string]*Item)
+	err := filepath.Walk(itemsPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		var items []*Item
+		decoder := json.NewDecoder(file)
+		decoder.DisallowUnknownFields()
+		if err = decoder.Decode(&items); err != nil {
+			return errors.Wrapf(err, "cannot decode %s", path)
+		}
+
+		for _, item := range items {
+			if _, ok := itemsDB[item.ID]; ok {
+				return fmt.Errorf("item ID %s duplicated", item.ID)
+			}
+			if item.Container != nil && len(item.Container.Contents) > 0 {
+				return fmt.Errorf("item %s cannot define its contents, use put resets instead", item.ID)
+			}
+			if item.Rarity != Common || len(item.Affixes) > 0 {
+				return fmt.Errorf("item %s cannot define its rarity nor its affixes, they are rolled when dropped", item.ID)
+			}
+			if item.RandomAffixes {
+				if item.Equipment == nil {
+					return fmt.Errorf("item %s cannot roll affixes, it is not equipment", item.ID)
+				}
+				item.affixes = affixesFor(affixes, item.Equipment.Slot)
+			}
+			itemsDB[item.ID] = item
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return itemsDB, nil
 }
 
 // GetPlayer returns a player from the player list
@@ -245,20 +461,110 @@ func (w *World) Finalize() {
 	w.SavePlayers()
 }
 
-// CreateBattle creates a new battle between the player and the mob
+// CreateBattle creates a new battle between the player and the mob, or makes the player switch to the mob
+// if both are already fighting on the same battle
 func (w *World) CreateBattle(playerID string, mob *Mob) {
+	w.removeFinishedBattles()
 	player := w.players[playerID]
 	playerBattle := w.GetPlayerBattle(player)
 	mobBattle := w.GetMobBattle(mob)
+	if playerBattle != nil && playerBattle.IsPvP() {
+		player.Notify("You have to finish your current fight first.")
+		return
+	}
+	if playerBattle != nil && playerBattle == mobBattle && !playerBattle.finishBattle() {
+		playerBattle.SwitchTarget(player, mob)
+		return
+	}
 	newBattle := mergeBattles(playerBattle, mobBattle)
 	newBattle.AddPlayer(player)
 	newBattle.AddMob(mob)
+	newBattle.Engage(player, mob)
+	for _, member := range w.freeAssistants(player, playerBattle, mobBattle) {
+		newBattle.AddPlayer(member)
+		newBattle.Engage(member, mob)
+		member.Notify(fmt.Sprintf("You assist %s!", player.Name))
+	}
 	w.RemovePlayerBattle(player)
 	w.RemoveMobBattle(mob)
 	w.battles = append(w.battles, newBattle)
 	newBattle.Start()
 }
 
+// CreatePvPBattle creates a new battle between the player and a rival, each with the members of their group.
+// If either of them is already fighting other players, the other one joins the opposite team.
+func (w *World) CreatePvPBattle(playerID string, rival *Player) {
+	w.removeFinishedBattles()
+	player := w.players[playerID]
+	playerBattle := w.GetPlayerBattle(player)
+	rivalBattle := w.GetPlayerBattle(rival)
+	switch {
+	case playerBattle != nil && !playerBattle.IsPvP():
+		player.Notify("You have to finish your current fight first.")
+		return
+	case rivalBattle != nil && (!rivalBattle.IsPvP() || (playerBattle != nil && playerBattle != rivalBattle)):
+		player.Notify(fmt.Sprintf("%s is busy with another fight.", rival.Name))
+		return
+	case playerBattle != nil && playerBattle == rivalBattle:
+		if !playerBattle.AreRivals(player, rival) {
+			player.Notify(fmt.Sprintf("%s is on your side!", rival.Name))
+			return
+		}
+		playerBattle.SwitchRival(player, rival)
+		return
+	}
+
+	b, playerTeam := playerBattle, 0
+	switch {
+	case playerBattle != nil:
+		playerTeam = playerBattle.TeamOf(player)
+	case rivalBattle != nil:
+		b = rivalBattle
+		playerTeam = 1 - rivalBattle.TeamOf(rival)
+	default:
+		b = &Battle{battleEnded: make(chan struct{})}
+		w.battles = append(w.battles, b)
+		defer b.Start()
+	}
+
+	player.Notify(fmt.Sprintf("You attack %s!", rival.Name))
+	rival.catchAsleep()
+	rival.Notify(fmt.Sprintf("%s attacks you!", player.Name))
+	b.joinTeam(player, playerTeam, rival, w.freeAssistants(player, b))
+	b.joinTeam(rival, 1-playerTeam, player, w.freeAssistants(rival, b))
+	b.EngageRival(player, rival)
+}
+
+// freeAssistants returns the assistants of the player who are not fighting on any battle besides the given ones,
+// so nobody is dragged into a second battle
+func (w *World) freeAssistants(player *Player, battles ...*Battle) []*Player {
+	free := []*Player{}
+	for _, member := range player.assistants() {
+		current := w.GetPlayerBattle(member)
+		busy := current != nil
+		for _, b := range battles {
+			if current == b {
+				busy = false
+			}
+		}
+		if !busy {
+			free = append(free, member)
+		}
+	}
+	return free
+}
+
+// removeFinishedBattles forgets the battles that already ended, so their former combatants are not dragged into new ones
+func (w *World) removeFinishedBattles() {
+	ongoing := []*Battle{}
+	for _, b := range w.battles {
+		if !b.finishBattle() {
+			ongoing = append(ongoing, b)
+		}
+	}
+	w.battles = ongoing
+}
+
 // GetPlayerBattle gets the battle where the player is fighting
 func (w *World) GetPlayerBattle(player *Player) *Battle {
 	for _, b := range w.battles {